@@ -0,0 +1,187 @@
+package storeit
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AggFunc 聚合函数
+type AggFunc string
+
+const (
+	AggCount         AggFunc = "COUNT"
+	AggCountDistinct AggFunc = "COUNT_DISTINCT"
+	AggSum           AggFunc = "SUM"
+	AggAvg           AggFunc = "AVG"
+	AggMin           AggFunc = "MIN"
+	AggMax           AggFunc = "MAX"
+)
+
+var aliasRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Agg GroupAggregate 的聚合项，Column 必须是模型的字段，AggCount 时可以为空表示 COUNT(*)。
+// Alias 为结果中的 key，为空时使用 func_列名，例如 sum_amount。
+type Agg struct {
+	Func   AggFunc
+	Column string
+	Alias  string
+}
+
+// Min 查询字段的最小值，dest 为指向结果的指针，没有记录时结果为 NULL，可以使用 sql.NullInt64 等类型接收
+func (r *GormStore[M]) Min(ctx context.Context, column string, dest any, criteria *Criteria) error {
+	return r.aggregate(ctx, criteria, Agg{Func: AggMin, Column: column}, func(db *gorm.DB) error {
+		return db.Scan(dest).Error
+	})
+}
+
+// Max 查询字段的最大值，dest 为指向结果的指针，没有记录时结果为 NULL，可以使用 sql.NullInt64 等类型接收
+func (r *GormStore[M]) Max(ctx context.Context, column string, dest any, criteria *Criteria) error {
+	return r.aggregate(ctx, criteria, Agg{Func: AggMax, Column: column}, func(db *gorm.DB) error {
+		return db.Scan(dest).Error
+	})
+}
+
+// CountDistinct 统计字段不同值的数量
+func (r *GormStore[M]) CountDistinct(ctx context.Context, column string, criteria *Criteria) (count int64, err error) {
+	err = r.aggregate(ctx, criteria, Agg{Func: AggCountDistinct, Column: column}, func(db *gorm.DB) error {
+		return db.Scan(&count).Error
+	})
+	return
+}
+
+// GroupAggregate 按 groupBy 分组聚合，每一行以分组字段和聚合项的 Alias 为 key。
+// criteria 的排序和分页会保留，可以按 Alias 排序取前 N 个分组。
+func (r *GormStore[M]) GroupAggregate(ctx context.Context, groupBy []string, criteria *Criteria, aggs ...Agg) ([]map[string]any, error) {
+	var model M
+	if len(aggs) == 0 {
+		return nil, fmt.Errorf("group aggregate requires at least one aggregation")
+	}
+	selects := make([]string, 0, len(groupBy)+len(aggs))
+	vars := make([]any, 0, len(groupBy)+len(aggs)*2)
+	groupColumns := make([]clause.Column, 0, len(groupBy))
+	for _, name := range groupBy {
+		column, err := r.modelColumn(name)
+		if err != nil {
+			return nil, err
+		}
+		selects = append(selects, "?")
+		vars = append(vars, column)
+		groupColumns = append(groupColumns, column)
+	}
+	for _, agg := range aggs {
+		query, args, err := r.aggregateExpr(agg)
+		if err != nil {
+			return nil, err
+		}
+		selects = append(selects, query)
+		vars = append(vars, args...)
+	}
+
+	db := r.presentRead(ctx, criteria).Model(&model).Select(strings.Join(selects, ", "), vars...)
+	if len(groupColumns) > 0 {
+		db = db.Clauses(clause.GroupBy{Columns: groupColumns})
+	}
+	rows, err := db.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]any, 0)
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			// mysql 等驱动会把文本返回为 []byte
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// aggregate 在去掉排序和分页的 criteria 上执行单个聚合
+func (r *GormStore[M]) aggregate(ctx context.Context, criteria *Criteria, agg Agg, scan func(db *gorm.DB) error) error {
+	var model M
+	c := criteria.Clone()
+	c.unsetOrder()
+	c.unsetLimit()
+	query, args, err := r.aggregateExpr(agg)
+	if err != nil {
+		return err
+	}
+	return scan(r.presentRead(ctx, c).Model(&model).Select(query, args...))
+}
+
+// aggregateExpr 生成 SUM(`column`) AS `alias` 形式的查询语句，默认的别名使用字段的列名
+func (r *GormStore[M]) aggregateExpr(agg Agg) (string, []any, error) {
+	var column clause.Column
+	if agg.Func != AggCount || agg.Column != "" {
+		var err error
+		if column, err = r.modelColumn(agg.Column); err != nil {
+			return "", nil, err
+		}
+	}
+	alias := agg.Alias
+	if alias == "" {
+		alias = strings.ToLower(string(agg.Func))
+		if column.Name != "" {
+			alias += "_" + column.Name
+		}
+	}
+	if !aliasRegexp.MatchString(alias) {
+		return "", nil, fmt.Errorf("invalid aggregate alias: %s", alias)
+	}
+	aliasColumn := clause.Column{Name: alias}
+	switch agg.Func {
+	case AggCount:
+		if column.Name == "" {
+			return "COUNT(*) AS ?", []any{aliasColumn}, nil
+		}
+		return "COUNT(?) AS ?", []any{column, aliasColumn}, nil
+	case AggCountDistinct:
+		return "COUNT(DISTINCT ?) AS ?", []any{column, aliasColumn}, nil
+	case AggSum, AggAvg, AggMin, AggMax:
+		return string(agg.Func) + "(?) AS ?", []any{column, aliasColumn}, nil
+	}
+	return "", nil, fmt.Errorf("unknown aggregate func: %s", agg.Func)
+}
+
+// modelColumn 校验字段属于模型，防止通过列名注入 SQL，字段可以带有模型的表名前缀
+func (r *GormStore[M]) modelColumn(name string) (clause.Column, error) {
+	s, err := r.parseSchema()
+	if err != nil {
+		return clause.Column{}, err
+	}
+	column := name
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		if strings.Trim(column[:i], "`\"") != s.Table {
+			return clause.Column{}, fmt.Errorf("column %s not found in %s", name, s.Name)
+		}
+		column = column[i+1:]
+	}
+	field := s.LookUpField(strings.Trim(column, "`\""))
+	if field == nil || field.DBName == "" {
+		return clause.Column{}, fmt.Errorf("column %s not found in %s", name, s.Name)
+	}
+	return clause.Column{Table: clause.CurrentTable, Name: field.DBName}, nil
+}
@@ -0,0 +1,116 @@
+package storeit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func seedAggregateModels(t *testing.T, store *GormStore[TestModel]) {
+	models := []TestModel{
+		{Name: "a", Age: 20, Score: 60},
+		{Name: "a", Age: 30, Score: 80},
+		{Name: "b", Age: 40, Score: 80},
+		{Name: "c", Age: 50, Score: 90},
+	}
+	assert.NoError(t, store.Creates(context.Background(), models).Error)
+}
+
+func TestGormStore_MinMaxCountDistinct(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+	seedAggregateModels(t, store)
+
+	var minAge, maxAge int
+	assert.NoError(t, store.Min(ctx, "age", &minAge, nil))
+	assert.Equal(t, 20, minAge)
+	assert.NoError(t, store.Max(ctx, "age", &maxAge, NewCriteria().WhereLt("age", 50).Limit(1).OrderAsc("age")))
+	assert.Equal(t, 40, maxAge)
+
+	// 带表名前缀的字段
+	assert.NoError(t, store.Max(ctx, "test_models.age", &maxAge, nil))
+	assert.Equal(t, 50, maxAge)
+
+	var maxName string
+	assert.NoError(t, store.Max(ctx, "Name", &maxName, nil))
+	assert.Equal(t, "c", maxName)
+
+	// 没有记录时结果为 NULL
+	var empty sql.NullInt64
+	assert.NoError(t, store.Min(ctx, "age", &empty, NewCriteria().WhereGt("age", 100)))
+	assert.False(t, empty.Valid)
+
+	count, err := store.CountDistinct(ctx, "name", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	count, err = store.CountDistinct(ctx, "score", NewCriteria().WhereGte("age", 30))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestGormStore_GroupAggregate(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+	seedAggregateModels(t, store)
+
+	rows, err := store.GroupAggregate(ctx, []string{"name"}, NewCriteria().OrderAsc("name"),
+		Agg{Func: AggCount},
+		Agg{Func: AggSum, Column: "age", Alias: "total_age"},
+		Agg{Func: AggMax, Column: "score"},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "a", rows[0]["name"])
+	assert.EqualValues(t, 2, rows[0]["count"])
+	assert.EqualValues(t, 50, rows[0]["total_age"])
+	assert.EqualValues(t, 80, rows[0]["max_score"])
+
+	// 按聚合结果排序取前 N 个分组
+	rows, err = store.GroupAggregate(ctx, []string{"score"}, NewCriteria().OrderDesc("cnt").Limit(1),
+		Agg{Func: AggCount, Alias: "cnt"})
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.EqualValues(t, 80, rows[0]["score"])
+	assert.EqualValues(t, 2, rows[0]["cnt"])
+
+	// 不分组
+	rows, err = store.GroupAggregate(ctx, nil, nil, Agg{Func: AggAvg, Column: "age"})
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.EqualValues(t, 35, rows[0]["avg_age"])
+
+	// 默认别名使用字段的列名
+	rows, err = store.GroupAggregate(ctx, nil, nil, Agg{Func: AggSum, Column: "test_models.age"}, Agg{Func: AggMin, Column: "Score"})
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.EqualValues(t, 140, rows[0]["sum_age"])
+	assert.EqualValues(t, 60, rows[0]["min_score"])
+}
+
+func TestGormStore_AggregateValidation(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	// 列名必须是模型的字段
+	_, err := store.Sum(ctx, "age) FROM test_models; --", nil)
+	assert.Error(t, err)
+	_, err = store.Avg(ctx, "unknown", nil)
+	assert.Error(t, err)
+	var v int
+	assert.Error(t, store.Min(ctx, "1", &v, nil))
+	_, err = store.CountDistinct(ctx, "unknown", nil)
+	assert.Error(t, err)
+	_, err = store.GroupAggregate(ctx, []string{"unknown"}, nil, Agg{Func: AggCount})
+	assert.Error(t, err)
+	_, err = store.GroupAggregate(ctx, []string{"name"}, nil, Agg{Func: AggSum, Column: "age", Alias: "a b"})
+	assert.Error(t, err)
+	_, err = store.GroupAggregate(ctx, []string{"name"}, nil, Agg{Func: "MEDIAN", Column: "age"})
+	assert.Error(t, err)
+	_, err = store.GroupAggregate(ctx, []string{"name"}, nil)
+	assert.Error(t, err)
+}
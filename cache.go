@@ -0,0 +1,265 @@
+package storeit
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Cache 查询结果缓存，实现需要是并发安全的。ttl 小于等于 0 表示不过期。
+type Cache interface {
+	Get(ctx context.Context, key string) (any, bool)
+	Set(ctx context.Context, key string, value any, ttl time.Duration)
+}
+
+// WithCache 为 FindByID 和 Find 开启缓存，同一 store 的写操作成功后会使该模型的缓存全部失效。
+// 事务中、ForcePrimary 以及包含 ScopeClosure 的查询不会使用缓存。
+// 事务中的写操作会在事务提交后再次使缓存失效；SetTx 的事务未结束前，该模型的查询不会写入缓存。
+// 缓存的结果是浅拷贝的，调用方不应修改结果中的 slice、map 等引用类型字段。
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(o *options) {
+		o.cache = cache
+		o.cacheTTL = ttl
+		o.pendingTxs = &pendingTxs{}
+	}
+}
+
+// cacheKey 生成缓存 key，key 中包含模型的缓存版本，写操作通过更新版本让旧的缓存失效。
+// 不能使用缓存时返回空字符串。
+func (r *GormStore[M]) cacheKey(ctx context.Context, kind string, value any) string {
+	if r.options.cache == nil || len(r.scopeClosures) > 0 || len(r.relationAggs) > 0 || r.forcePrimary || r.tx != nil || TxFromContext(ctx) != nil {
+		return ""
+	}
+	s, err := r.parseSchema()
+	if err != nil {
+		return ""
+	}
+	if open, done := r.options.pendingTxs.settle(s.Table); open || done {
+		// 刚结束的事务可能还没有完成 COMMIT，本次查询同样不写入缓存
+		if done {
+			r.bumpCacheGeneration(ctx, s.Table)
+		}
+		return ""
+	}
+	var tenant any
+	if r.options.tenantColumn != "" && !r.withoutTenant {
+		if tenant, err = r.tenant(ctx); err != nil {
+			return ""
+		}
+	}
+	state, err := json.Marshal([]any{r.columns, r.hidden, r.unscoped, r.onlyTrashed, tenant, value})
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("storeit:%s:%v:%s:%x", s.Table, r.cacheGeneration(ctx, s.Table), kind, sha1.Sum(state))
+}
+
+func (r *GormStore[M]) cacheGeneration(ctx context.Context, table string) any {
+	key := "storeit:" + table + ":generation"
+	if generation, ok := r.options.cache.Get(ctx, key); ok {
+		return generation
+	}
+	// 版本被淘汰时生成新的版本，避免读到淘汰前的旧缓存
+	generation := time.Now().UnixNano()
+	r.options.cache.Set(ctx, key, generation, 0)
+	return generation
+}
+
+// invalidateCache 使模型的缓存失效。事务中写入的数据在提交前对其他连接不可见，
+// 提交前读到的旧数据会以新的版本写入缓存，因此事务提交后需要再次失效。
+func (r *GormStore[M]) invalidateCache(ctx context.Context) {
+	if r.options.cache == nil {
+		return
+	}
+	s, err := r.parseSchema()
+	if err != nil {
+		return
+	}
+	r.bumpCacheGeneration(ctx, s.Table)
+	if r.tx != nil {
+		// SetTx 的事务由调用方提交，无法得知提交的时机，在读取时检查事务是否结束
+		if tx := sqlTxOf(r.tx); tx != nil {
+			r.options.pendingTxs.add(s.Table, tx)
+		}
+		return
+	}
+	afterCommit(ctx, func() {
+		r.bumpCacheGeneration(ctx, s.Table)
+	})
+}
+
+func (r *GormStore[M]) bumpCacheGeneration(ctx context.Context, table string) {
+	r.options.cache.Set(ctx, "storeit:"+table+":generation", time.Now().UnixNano(), 0)
+}
+
+// pendingTxs 记录通过 SetTx 写入过且尚未结束的事务
+type pendingTxs struct {
+	mu     sync.Mutex
+	tables map[string][]*sql.Tx
+}
+
+func (p *pendingTxs) add(table string, tx *sql.Tx) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pending := range p.tables[table] {
+		if pending == tx {
+			return
+		}
+	}
+	if p.tables == nil {
+		p.tables = make(map[string][]*sql.Tx)
+	}
+	p.tables[table] = append(p.tables[table], tx)
+}
+
+// settle 移除已经结束的事务，返回表上是否还有未结束的事务以及是否有事务刚刚结束
+func (p *pendingTxs) settle(table string) (open, done bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending := p.tables[table]
+	if len(pending) == 0 {
+		return false, false
+	}
+	remaining := pending[:0]
+	for _, tx := range pending {
+		if txDone(tx) {
+			done = true
+			continue
+		}
+		remaining = append(remaining, tx)
+	}
+	if len(remaining) == 0 {
+		delete(p.tables, table)
+	} else {
+		p.tables[table] = remaining
+	}
+	return len(remaining) > 0, done
+}
+
+// sqlTxOf 返回 db 使用的 *sql.Tx，db 不在事务中时返回 nil
+func sqlTxOf(db *gorm.DB) *sql.Tx {
+	pool := db.Statement.ConnPool
+	if prepared, ok := pool.(*gorm.PreparedStmtTX); ok {
+		pool = prepared.Tx
+	}
+	tx, _ := pool.(*sql.Tx)
+	return tx
+}
+
+// txDone 判断事务是否已经提交或回滚。事务结束后 StmtContext 返回 ErrTxDone，
+// 未结束时因为 stmt 不属于同一个连接池返回其他错误，两种情况都不会访问数据库。
+func txDone(tx *sql.Tx) bool {
+	return errors.Is(tx.StmtContext(context.Background(), &sql.Stmt{}).Close(), sql.ErrTxDone)
+}
+
+func (r *GormStore[M]) loadCache(ctx context.Context, key string) any {
+	if key == "" {
+		return nil
+	}
+	value, _ := r.options.cache.Get(ctx, key)
+	return value
+}
+
+func (r *GormStore[M]) storeCache(ctx context.Context, key string, value any) {
+	if key == "" {
+		return
+	}
+	r.options.cache.Set(ctx, key, value, r.options.cacheTTL)
+}
+
+// fingerprint 返回 Criteria 的规范化描述，包含 ScopeClosure 等无法描述的条件时返回 false
+func (c *Criteria) fingerprint() (string, bool) {
+	if c == nil {
+		return "", true
+	}
+	if c.opaque || c.usesScope() {
+		return "", false
+	}
+	data, err := c.MarshalJSON()
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// LRUCache 基于内存的 LRU 缓存
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewLRUCache 创建最多保存 capacity 个条目的 LRU 缓存
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+func (c *LRUCache) Get(_ context.Context, key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeElement(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *LRUCache) Set(_ context.Context, key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Len 返回缓存中的条目数量，包含已过期但尚未清理的条目
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*lruEntry).key)
+}
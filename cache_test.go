@@ -0,0 +1,220 @@
+package storeit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2)
+
+	cache.Set(ctx, "a", 1, 0)
+	cache.Set(ctx, "b", 2, 0)
+	value, ok := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// 淘汰最久未使用的 b
+	cache.Set(ctx, "c", 3, 0)
+	_, ok = cache.Get(ctx, "b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	// 覆盖已有的 key
+	cache.Set(ctx, "a", 10, 0)
+	value, _ = cache.Get(ctx, "a")
+	assert.Equal(t, 10, value)
+
+	// 过期
+	cache.Set(ctx, "d", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get(ctx, "d")
+	assert.False(t, ok)
+}
+
+func TestGormStore_CacheFindByID(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db, WithCache(NewLRUCache(100), time.Minute))
+	ctx := context.Background()
+
+	model := &TestModel{Name: "User 1", Age: 20}
+	err := store.Create(ctx, model).Error
+	assert.NoError(t, err)
+
+	found, err := store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, found.Age)
+
+	// 绕过 store 修改数据库，命中缓存返回旧值
+	err = db.Model(&TestModel{}).Where("id = ?", model.ID).Update("age", 99).Error
+	assert.NoError(t, err)
+	found, err = store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, found.Age)
+
+	// 修改返回值不影响缓存
+	found.Name = "changed"
+	found, err = store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "User 1", found.Name)
+
+	// ForcePrimary 不使用缓存
+	found, err = store.ForcePrimary().FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 99, found.Age)
+
+	// store 的写操作使缓存失效
+	err = store.UpdateById(ctx, model.ID, "age", 30).Error
+	assert.NoError(t, err)
+	found, err = store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, found.Age)
+
+	err = store.DeleteById(ctx, model.ID).Error
+	assert.NoError(t, err)
+	_, err = store.FindByID(ctx, model.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestGormStore_CacheFind(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db, WithCache(NewLRUCache(100), time.Minute))
+	ctx := context.Background()
+
+	err := store.Creates(ctx, []TestModel{{Name: "User 1", Age: 20}, {Name: "User 2", Age: 25}}).Error
+	assert.NoError(t, err)
+
+	criteria := NewCriteria().WhereGte("age", 20).OrderAsc("age")
+	found, err := store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	err = db.Create(&TestModel{Name: "User 3", Age: 30}).Error
+	assert.NoError(t, err)
+
+	// 相同条件的新 Criteria 命中缓存
+	found, err = store.Find(ctx, NewCriteria().WhereGte("age", 20).OrderAsc("age"))
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	// 不同条件不命中
+	found, err = store.Find(ctx, NewCriteria().WhereGte("age", 21))
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	// 不同的列不命中
+	found, err = store.Columns([]string{"id", "name"}).Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+	assert.Zero(t, found[0].Age)
+
+	// 包含 ScopeClosure 的条件不使用缓存
+	found, err = store.Find(ctx, NewCriteria().ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("age >= ?", 20)
+	}))
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+
+	// 事务中不使用缓存
+	err = Transaction(ctx, db, func(ctx context.Context) error {
+		found, err := store.Find(ctx, criteria)
+		assert.Len(t, found, 3)
+		return err
+	})
+	assert.NoError(t, err)
+
+	// Updates 使缓存失效
+	err = store.Updates(ctx, map[string]any{"email": "x@test.com"}, NewCriteria().WhereGt("age", 100)).Error
+	assert.NoError(t, err)
+	found, err = store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+}
+
+func TestCriteria_fingerprint(t *testing.T) {
+	var nilCriteria *Criteria
+	fingerprint, ok := nilCriteria.fingerprint()
+	assert.True(t, ok)
+	assert.Empty(t, fingerprint)
+
+	a, ok := NewCriteria().Where("age = ?", 1).OrderDesc("age").Limit(10).fingerprint()
+	assert.True(t, ok)
+	b, _ := NewCriteria().Where("age = ?", 1).OrderDesc("age").Limit(10).fingerprint()
+	assert.Equal(t, a, b)
+	c, _ := NewCriteria().Where("age = ?", "1").OrderDesc("age").Limit(10).fingerprint()
+	assert.NotEqual(t, a, c)
+
+	_, ok = NewCriteria().Where("age = ?", gorm.Expr("1")).fingerprint()
+	assert.False(t, ok)
+	_, ok = NewCriteria().AddPreload("Orders", func(tx *gorm.DB) *gorm.DB { return tx }).fingerprint()
+	assert.False(t, ok)
+}
+
+// setupWALTestDB 使用 WAL 模式的文件数据库，事务提交前其他连接读到的是旧数据
+func setupWALTestDB(t *testing.T) *gorm.DB {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_journal_mode=WAL&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&TestModel{}))
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestGormStore_CacheInvalidateAfterCommit(t *testing.T) {
+	db := setupWALTestDB(t)
+	store := New[TestModel](db, WithCache(NewLRUCache(100), 0))
+	ctx := context.Background()
+
+	model := &TestModel{Name: "User 1", Age: 20}
+	assert.NoError(t, store.Create(ctx, model).Error)
+
+	// SetTx：提交前事务外的查询读到旧数据，不能写入缓存
+	tx := db.Begin()
+	assert.NoError(t, store.SetTx(tx).UpdatesById(ctx, model.ID, map[string]any{"age": 99}).Error)
+	found, err := store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, found.Age)
+	assert.NoError(t, tx.Commit().Error)
+	found, err = store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 99, found.Age)
+
+	// Transaction：提交后缓存再次失效
+	err = Transaction(ctx, db, func(txCtx context.Context) error {
+		if err := store.UpdatesById(txCtx, model.ID, map[string]any{"age": 30}).Error; err != nil {
+			return err
+		}
+		found, err := store.FindByID(ctx, model.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 99, found.Age)
+		return nil
+	})
+	assert.NoError(t, err)
+	found, err = store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, found.Age)
+
+	// 回滚的事务不影响缓存
+	tx = db.Begin()
+	assert.NoError(t, store.SetTx(tx).UpdatesById(ctx, model.ID, map[string]any{"age": 40}).Error)
+	assert.NoError(t, tx.Rollback().Error)
+	found, err = store.FindByID(ctx, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, found.Age)
+}
@@ -0,0 +1,101 @@
+package storeit
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Result 写操作的结果
+type Result struct {
+	RowsAffected int64 `json:"rows_affected"`
+}
+
+// CheckedStore 提供与 GormStore 平行的 API，返回 (Result, error) 而不是 *gorm.DB，
+// 错误统一经过 TranslateError 转换为 ErrNotFound、ErrDuplicateKey 等哨兵错误，
+// 更新和删除没有影响任何行时返回 ErrNoRowsAffected。
+type CheckedStore[M any] struct {
+	store *GormStore[M]
+}
+
+// Checked 返回基于当前 store 的 CheckedStore
+func (r *GormStore[M]) Checked() *CheckedStore[M] {
+	return &CheckedStore[M]{store: r}
+}
+
+func (r *CheckedStore[M]) Create(ctx context.Context, model *M) (Result, error) {
+	return newResult(r.store.Create(ctx, model))
+}
+
+func (r *CheckedStore[M]) Creates(ctx context.Context, models []M) (Result, error) {
+	return newResult(r.store.Creates(ctx, models))
+}
+
+func (r *CheckedStore[M]) CreateInBatches(ctx context.Context, models []M, batchSize int) (Result, error) {
+	return newResult(r.store.CreateInBatches(ctx, models, batchSize))
+}
+
+func (r *CheckedStore[M]) Save(ctx context.Context, model M) (Result, error) {
+	return newResult(r.store.Save(ctx, model))
+}
+
+func (r *CheckedStore[M]) Delete(ctx context.Context, model *M) (Result, error) {
+	return checkAffected(newResult(r.store.Delete(ctx, model)))
+}
+
+func (r *CheckedStore[M]) Deletes(ctx context.Context, criteria *Criteria) (Result, error) {
+	return checkAffected(newResult(r.store.Deletes(ctx, criteria)))
+}
+
+func (r *CheckedStore[M]) DeleteById(ctx context.Context, id any) (Result, error) {
+	return checkAffected(newResult(r.store.DeleteById(ctx, id)))
+}
+
+func (r *CheckedStore[M]) ForceDelete(ctx context.Context, criteria *Criteria) (Result, error) {
+	return checkAffected(newResult(r.store.ForceDelete(ctx, criteria)))
+}
+
+func (r *CheckedStore[M]) Restore(ctx context.Context, criteria *Criteria) (Result, error) {
+	return checkAffected(newResult(r.store.Restore(ctx, criteria)))
+}
+
+func (r *CheckedStore[M]) RestoreById(ctx context.Context, id any) (Result, error) {
+	return checkAffected(newResult(r.store.RestoreById(ctx, id)))
+}
+
+func (r *CheckedStore[M]) Update(ctx context.Context, column string, value any, criteria *Criteria) (Result, error) {
+	return checkAffected(newResult(r.store.Update(ctx, column, value, criteria)))
+}
+
+func (r *CheckedStore[M]) Updates(ctx context.Context, attributes any, criteria *Criteria) (Result, error) {
+	return checkAffected(newResult(r.store.Updates(ctx, attributes, criteria)))
+}
+
+func (r *CheckedStore[M]) UpdateById(ctx context.Context, id any, column string, value any) (Result, error) {
+	return checkAffected(newResult(r.store.UpdateById(ctx, id, column, value)))
+}
+
+func (r *CheckedStore[M]) UpdatesById(ctx context.Context, id any, updates any) (Result, error) {
+	return checkAffected(newResult(r.store.UpdatesById(ctx, id, updates)))
+}
+
+func (r *CheckedStore[M]) FindByID(ctx context.Context, id any) (*M, error) {
+	model, err := r.store.FindByID(ctx, id)
+	return model, TranslateError(err)
+}
+
+func (r *CheckedStore[M]) First(ctx context.Context, criteria *Criteria) (*M, error) {
+	model, err := r.store.First(ctx, criteria)
+	return model, TranslateError(err)
+}
+
+func newResult(tx *gorm.DB) (Result, error) {
+	return Result{RowsAffected: tx.RowsAffected}, TranslateError(tx.Error)
+}
+
+func checkAffected(result Result, err error) (Result, error) {
+	if err == nil && result.RowsAffected == 0 {
+		err = ErrNoRowsAffected
+	}
+	return result, err
+}
@@ -0,0 +1,79 @@
+package storeit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type UniqueModel struct {
+	ID    int    `gorm:"primarykey"`
+	Email string `gorm:"size:255;uniqueIndex"`
+}
+
+func TestCheckedStore_Write(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db).Checked()
+	ctx := context.Background()
+
+	model := &TestModel{Name: "User 1", Age: 20}
+	result, err := store.Create(ctx, model)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.RowsAffected)
+
+	result, err = store.Creates(ctx, []TestModel{{Name: "User 2", Age: 25}, {Name: "User 3", Age: 30}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.RowsAffected)
+
+	result, err = store.UpdateById(ctx, model.ID, "age", 21)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.RowsAffected)
+
+	result, err = store.Updates(ctx, map[string]any{"age": 40}, NewCriteria().WhereGte("age", 25))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.RowsAffected)
+
+	// 没有影响任何行
+	_, err = store.UpdatesById(ctx, 9999, map[string]any{"age": 1})
+	assert.ErrorIs(t, err, ErrNoRowsAffected)
+	_, err = store.Update(ctx, "age", 1, NewCriteria().WhereGt("age", 100))
+	assert.ErrorIs(t, err, ErrNoRowsAffected)
+	_, err = store.DeleteById(ctx, 9999)
+	assert.ErrorIs(t, err, ErrNoRowsAffected)
+	_, err = store.Deletes(ctx, NewCriteria().WhereGt("age", 100))
+	assert.ErrorIs(t, err, ErrNoRowsAffected)
+
+	result, err = store.Delete(ctx, model)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.RowsAffected)
+
+	// 记录不存在
+	_, err = store.FindByID(ctx, model.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = store.First(ctx, NewCriteria().Where("name = ?", "nobody"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	found, err := store.First(ctx, NewCriteria().Where("name = ?", "User 2"))
+	assert.NoError(t, err)
+	assert.Equal(t, 40, found.Age)
+}
+
+func TestCheckedStore_DuplicateKey(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&UniqueModel{})
+	assert.NoError(t, err)
+	store := New[UniqueModel](db).Checked()
+	ctx := context.Background()
+
+	_, err = store.Create(ctx, &UniqueModel{Email: "a@test.com"})
+	assert.NoError(t, err)
+
+	_, err = store.Create(ctx, &UniqueModel{Email: "a@test.com"})
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+
+	_, err = store.CreateInBatches(ctx, []UniqueModel{{Email: "b@test.com"}, {Email: "b@test.com"}}, 10)
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+}
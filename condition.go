@@ -0,0 +1,420 @@
+package storeit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	conditionWhere   = "where"
+	conditionOr      = "or"
+	conditionNot     = "not"
+	conditionHaving  = "having"
+	conditionJoins   = "joins"
+	conditionPreload = "preload"
+	conditionScope   = "scope"
+
+	logicAnd = "and"
+	logicOr  = "or"
+)
+
+var (
+	identifierRegexp = regexp.MustCompile("^(?:[`\"]?[A-Za-z_][A-Za-z0-9_]*[`\"]?\\.)?[`\"]?[A-Za-z_][A-Za-z0-9_]*[`\"]?$")
+	orderRegexp      = regexp.MustCompile("^(?:[`\"]?[A-Za-z_][A-Za-z0-9_]*[`\"]?\\.)?[`\"]?[A-Za-z_][A-Za-z0-9_]*[`\"]?(?i: (?:ASC|DESC))?$")
+)
+
+// Condition 条件树的节点，有三种形式：
+//   - 字段条件：Field + Operator + Value，Operator 同 criteria tag 的 operator
+//   - 原始条件：Query + Args，对应 Where(query, args...)
+//   - 条件组：Logic(and/or) + Children，子条件用括号组合
+//
+// Clause 表示条件作用的位置，可选 where(默认)、or、not、having、joins、preload、scope，scope 的 Query 为命名 scope 的名称。
+// 条件组的子条件只能使用 where、or、not、scope，or 的子条件与前一个条件使用 OR 连接，其余使用 Logic 连接。
+type Condition struct {
+	Clause   string      `json:"clause,omitempty"`
+	Field    string      `json:"field,omitempty"`
+	Operator string      `json:"operator,omitempty"`
+	Value    any         `json:"value,omitempty"`
+	Query    string      `json:"query,omitempty"`
+	Args     []any       `json:"args,omitempty"`
+	Logic    string      `json:"logic,omitempty"`
+	Children []Condition `json:"children,omitempty"`
+}
+
+// expression 将条件渲染为 SQL 片段和参数，字段名使用 quote 转义
+func (cond Condition) expression(quote func(string) string) (string, []any, error) {
+	switch {
+	case len(cond.Children) > 0:
+		separator := " AND "
+		switch strings.ToLower(cond.Logic) {
+		case "", logicAnd:
+		case logicOr:
+			separator = " OR "
+		default:
+			return "", nil, fmt.Errorf("unknown criteria logic: %s", cond.Logic)
+		}
+		var builder strings.Builder
+		var args []any
+		builder.WriteString("(")
+		for i, child := range cond.Children {
+			query, childArgs, err := child.expression(quote)
+			if err != nil {
+				return "", nil, err
+			}
+			if child.Query != "" {
+				query = "(" + query + ")"
+			}
+			connector := separator
+			switch child.Clause {
+			case "", conditionWhere:
+			case conditionOr:
+				connector = " OR "
+			case conditionNot:
+				if child.Field != "" {
+					query = "(" + query + ")"
+				}
+				query = "NOT " + query
+			default:
+				return "", nil, fmt.Errorf("criteria group child can not use clause: %s", child.Clause)
+			}
+			if i > 0 {
+				builder.WriteString(connector)
+			}
+			builder.WriteString(query)
+			args = append(args, childArgs...)
+		}
+		builder.WriteString(")")
+		return builder.String(), args, nil
+	case cond.Field != "":
+		spec, err := buildFieldCondition(quote, cond.Operator, cond.Field, cond.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		return spec.query, spec.args, nil
+	case cond.Query != "":
+		return cond.Query, cond.Args, nil
+	}
+	return "", nil, errors.New("empty criteria condition")
+}
+
+// validate 校验条件中的字段名，用于从 JSON 重建的条件
+func (cond Condition) validate() error {
+	if cond.Field != "" && !identifierRegexp.MatchString(cond.Field) {
+		return fmt.Errorf("invalid criteria field: %s", cond.Field)
+	}
+	for _, child := range cond.Children {
+		if err := child.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closure 将条件转换为作用在 gorm.DB 上的闭包
+func (cond Condition) closure() (gormClosure, error) {
+	switch cond.Clause {
+	case conditionJoins, conditionPreload:
+		if cond.Query == "" || cond.Field != "" || len(cond.Children) > 0 {
+			return nil, fmt.Errorf("criteria %s condition requires query", cond.Clause)
+		}
+		if cond.Clause == conditionJoins {
+			return func(tx *gorm.DB) *gorm.DB {
+				return tx.Joins(cond.Query, cond.Args...)
+			}, nil
+		}
+		return func(tx *gorm.DB) *gorm.DB {
+			return tx.Preload(cond.Query, cond.Args...)
+		}, nil
+	case conditionScope:
+		if cond.Query == "" || cond.Field != "" || len(cond.Children) > 0 || len(cond.Args) > 0 {
+			return nil, errors.New("criteria scope condition requires name")
+		}
+		return func(tx *gorm.DB) *gorm.DB {
+			return applyScope(tx, cond.Query)
+		}, nil
+	}
+	var apply func(tx *gorm.DB, query any, args ...any) *gorm.DB
+	switch cond.Clause {
+	case "", conditionWhere:
+		apply = (*gorm.DB).Where
+	case conditionOr:
+		apply = (*gorm.DB).Or
+	case conditionNot:
+		apply = (*gorm.DB).Not
+	case conditionHaving:
+		apply = (*gorm.DB).Having
+	default:
+		return nil, fmt.Errorf("unknown criteria clause: %s", cond.Clause)
+	}
+	if len(cond.Children) > 0 && cond.usesScope() {
+		return cond.groupClosure(apply)
+	}
+	// 提前校验条件，字段名在查询时按数据库的方言转义
+	if _, _, err := cond.expression(QuoteReservedWord); err != nil {
+		return nil, err
+	}
+	return func(tx *gorm.DB) *gorm.DB {
+		query, args, err := cond.expression(func(field string) string {
+			return QuoteIdentifier(tx, field)
+		})
+		if err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+		return apply(tx, query, args...)
+	}, nil
+}
+
+// usesScope 判断条件或其子条件是否引用了命名 scope
+func (cond Condition) usesScope() bool {
+	if cond.Clause == conditionScope {
+		return true
+	}
+	for _, child := range cond.Children {
+		if child.usesScope() {
+			return true
+		}
+	}
+	return false
+}
+
+// groupClosure 条件组引用了命名 scope 时，scope 的内容在查询时才能确定，交给 gorm 组合条件组
+func (cond Condition) groupClosure(apply func(tx *gorm.DB, query any, args ...any) *gorm.DB) (gormClosure, error) {
+	logic := conditionWhere
+	switch strings.ToLower(cond.Logic) {
+	case "", logicAnd:
+	case logicOr:
+		logic = conditionOr
+	default:
+		return nil, fmt.Errorf("unknown criteria logic: %s", cond.Logic)
+	}
+	connectors := make([]string, 0, len(cond.Children))
+	closures := make([]gormClosure, 0, len(cond.Children))
+	for _, child := range cond.Children {
+		connector := logic
+		switch child.Clause {
+		case "", conditionWhere, conditionScope:
+		case conditionOr, conditionNot:
+			connector = child.Clause
+		default:
+			return nil, fmt.Errorf("criteria group child can not use clause: %s", child.Clause)
+		}
+		if child.Clause != conditionScope {
+			child.Clause = conditionWhere
+		}
+		closure, err := child.closure()
+		if err != nil {
+			return nil, err
+		}
+		connectors = append(connectors, connector)
+		closures = append(closures, closure)
+	}
+	return func(tx *gorm.DB) *gorm.DB {
+		if tx.Error != nil {
+			return tx
+		}
+		group := groupSession(tx)
+		for i, closure := range closures {
+			sub := closure(groupSession(tx))
+			// 条件组的错误不会传递到外层查询
+			if sub.Error != nil {
+				_ = tx.AddError(sub.Error)
+				return tx
+			}
+			switch connectors[i] {
+			case conditionOr:
+				group = group.Or(sub)
+			case conditionNot:
+				group = group.Not(sub)
+			default:
+				group = group.Where(sub)
+			}
+		}
+		return apply(tx, group)
+	}, nil
+}
+
+// groupSession 返回用于构建条件组的空查询，保留 store 注册的命名 scope
+func groupSession(tx *gorm.DB) *gorm.DB {
+	group := tx.Session(&gorm.Session{NewDB: true})
+	if scopes, ok := tx.Get(scopesSettingKey); ok {
+		group = group.Set(scopesSettingKey, scopes)
+	}
+	return group
+}
+
+// groupWhere 将 WHERE 中从 from 开始的条件用括号包裹，避免其中的 OR 条件绕过之后追加的条件。
+// 做法同 gorm 的 SoftDeleteQueryClause，只有包含 OR 条件时才包裹。
+type groupWhere struct {
+	from int
+}
+
+func (g groupWhere) ModifyStatement(stmt *gorm.Statement) {
+	c, ok := stmt.Clauses["WHERE"]
+	if !ok {
+		return
+	}
+	where, ok := c.Expression.(clause.Where)
+	if !ok || len(where.Exprs) <= g.from {
+		return
+	}
+	for _, expr := range where.Exprs[g.from:] {
+		if _, ok := expr.(clause.OrConditions); ok {
+			exprs := append([]clause.Expression(nil), where.Exprs[:g.from]...)
+			where.Exprs = append(exprs, clause.And(where.Exprs[g.from:]...))
+			c.Expression = where
+			stmt.Clauses["WHERE"] = c
+			return
+		}
+	}
+}
+
+// Build 实现 clause.Expression，groupWhere 只用于修改 Statement
+func (groupWhere) Build(clause.Builder) {}
+
+// addCondition 记录条件并添加对应的闭包
+func (c *Criteria) addCondition(cond Condition) error {
+	closure, err := cond.closure()
+	if err != nil {
+		return err
+	}
+	c.scopeClosures = append(c.scopeClosures, closure)
+	c.conditions = append(c.conditions, cond)
+	return nil
+}
+
+// rawCondition 添加原始条件，query 不是字符串或参数无法序列化时退化为不可序列化的闭包
+func (c *Criteria) rawCondition(clauseName string, query any, args []any, closure gormClosure) *Criteria {
+	if q, ok := query.(string); ok && q != "" && serializable(args) {
+		if err := c.addCondition(Condition{Clause: clauseName, Query: q, Args: args}); err == nil {
+			return c
+		}
+	}
+	c.scopeClosures = append(c.scopeClosures, closure)
+	c.opaque = true
+	return c
+}
+
+func (c *Criteria) fieldCondition(operator, field string, value any) *Criteria {
+	return c.condition(Condition{Field: field, Operator: operator, Value: value})
+}
+
+// condition 添加条件，条件不合法时在查询时返回错误
+func (c *Criteria) condition(cond Condition) *Criteria {
+	if err := c.addCondition(cond); err != nil {
+		return c.addError(err)
+	}
+	return c
+}
+
+// serializable 判断参数能否序列化为 JSON，子查询、表达式和函数都不能序列化
+func serializable(args []any) bool {
+	for _, arg := range args {
+		switch arg.(type) {
+		case *gorm.DB, clause.Expression, gormClosure, func(*gorm.DB) *gorm.DB:
+			return false
+		}
+	}
+	_, err := json.Marshal(args)
+	return err == nil
+}
+
+type criteriaJSON struct {
+	Conditions []Condition `json:"conditions,omitempty"`
+	Orders     []string    `json:"orders,omitempty"`
+	Group      string      `json:"group,omitempty"`
+	Limit      int         `json:"limit,omitempty"`
+	Offset     int         `json:"offset,omitempty"`
+	Page       int         `json:"page,omitempty"`
+	Cursor     string      `json:"cursor,omitempty"`
+}
+
+// MarshalJSON 将 Criteria 序列化为 JSON，包含 ScopeClosure、子查询等无法序列化的条件时返回错误
+func (c *Criteria) MarshalJSON() ([]byte, error) {
+	if c.opaque {
+		return nil, errors.New("criteria contains conditions that can not be serialized")
+	}
+	return json.Marshal(criteriaJSON{
+		Conditions: c.conditions,
+		Orders:     c.orders,
+		Group:      c.group,
+		Limit:      c.limit,
+		Offset:     c.offset,
+		Page:       c.page,
+		Cursor:     c.cursor,
+	})
+}
+
+// UnmarshalJSON 从 JSON 重建 Criteria，会校验条件和排序
+func (c *Criteria) UnmarshalJSON(data []byte) error {
+	var raw criteriaJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	criteria := Criteria{
+		group:  raw.Group,
+		limit:  raw.Limit,
+		offset: raw.Offset,
+		page:   raw.Page,
+		cursor: raw.Cursor,
+	}
+	for _, cond := range raw.Conditions {
+		if err := cond.validate(); err != nil {
+			return err
+		}
+		if err := criteria.addCondition(normalizeCondition(cond)); err != nil {
+			return err
+		}
+	}
+	for _, order := range raw.Orders {
+		if !orderRegexp.MatchString(order) {
+			return fmt.Errorf("invalid criteria order: %s", order)
+		}
+		criteria.orders = append(criteria.orders, order)
+	}
+	*c = criteria
+	return nil
+}
+
+// ParseCriteriaJSON 从 MarshalJSON 生成的 JSON 重建 Criteria。
+// 原始条件(Query)会原样拼接到 SQL 中，不要解析来自不可信客户端的 JSON。
+func ParseCriteriaJSON(data []byte) (*Criteria, error) {
+	criteria := NewCriteria()
+	if err := json.Unmarshal(data, criteria); err != nil {
+		return nil, err
+	}
+	return criteria, nil
+}
+
+// normalizeCondition 将 JSON 解码得到的整数 float64 还原为 int64
+func normalizeCondition(cond Condition) Condition {
+	cond.Value = normalizeJSONValue(cond.Value)
+	for i, arg := range cond.Args {
+		cond.Args[i] = normalizeJSONValue(arg)
+	}
+	for i, child := range cond.Children {
+		cond.Children[i] = normalizeCondition(child)
+	}
+	return cond
+}
+
+func normalizeJSONValue(value any) any {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeJSONValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,133 @@
+package storeit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCriteria_JSONRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	models := []TestModel{
+		{Name: "Alice", Age: 20, Email: "alice@example.com"},
+		{Name: "Bob", Age: 30, Email: "bob@test.com"},
+		{Name: "Carol", Age: 40, Email: "carol@example.com"},
+		{Name: "Dave", Age: 50},
+	}
+	for i := range models {
+		assert.NoError(t, store.Create(ctx, &models[i]).Error)
+	}
+
+	type Search struct {
+		Keyword string `criteria:"name,email:like"`
+		Ages    string `criteria:"age:in"`
+	}
+	criteria, err := ExtractCriteria(Search{Keyword: "example", Ages: "20,30,40,50"})
+	assert.NoError(t, err)
+	criteria.WhereGte("age", 20).WhereNotNull("email").Where("score = ?", 0).OrderDesc("age").Limit(10).Page(1)
+
+	data, err := json.Marshal(criteria)
+	assert.NoError(t, err)
+	parsed, err := ParseCriteriaJSON(data)
+	assert.NoError(t, err)
+
+	// 重建后的 Criteria 序列化结果和查询结果与原来一致
+	again, err := json.Marshal(parsed)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+
+	expected, err := store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	actual, err := store.Find(ctx, parsed)
+	assert.NoError(t, err)
+	assert.Len(t, actual, 2)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, "Carol", actual[0].Name)
+	assert.Equal(t, 10, parsed.GetLimit())
+}
+
+func TestCriteria_JSONConditions(t *testing.T) {
+	data := []byte(`{
+		"conditions": [
+			{"field": "age", "operator": "between", "value": [20, 40]},
+			{"logic": "or", "children": [
+				{"field": "name", "operator": "eq", "value": "Alice"},
+				{"query": "email LIKE ?", "args": ["%test.com"]}
+			]}
+		],
+		"orders": ["age DESC", "name"]
+	}`)
+	criteria, err := ParseCriteriaJSON(data)
+	assert.NoError(t, err)
+	assert.Len(t, criteria.scopeClosures, 2)
+	// 整数值还原为 int64
+	assert.Equal(t, []any{int64(20), int64(40)}, criteria.conditions[0].Value)
+
+	query, args, err := criteria.conditions[1].expression(QuoteReservedWord)
+	assert.NoError(t, err)
+	assert.Equal(t, "(name = ? OR (email LIKE ?))", query)
+	assert.Equal(t, []any{"Alice", "%test.com"}, args)
+
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+	assert.NoError(t, store.Create(ctx, &TestModel{Name: "Alice", Age: 20}).Error)
+	assert.NoError(t, store.Create(ctx, &TestModel{Name: "Bob", Age: 30, Email: "bob@test.com"}).Error)
+	assert.NoError(t, store.Create(ctx, &TestModel{Name: "Carol", Age: 30}).Error)
+	found, err := store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, "Bob", found[0].Name)
+}
+
+func TestCriteria_JSONErrors(t *testing.T) {
+	// 未知 operator
+	_, err := ParseCriteriaJSON([]byte(`{"conditions":[{"field":"age","operator":"foo","value":1}]}`))
+	assert.Error(t, err)
+	// 非法字段名
+	_, err = ParseCriteriaJSON([]byte(`{"conditions":[{"field":"age = 1 OR 1","operator":"eq","value":1}]}`))
+	assert.Error(t, err)
+	// 非法排序
+	_, err = ParseCriteriaJSON([]byte(`{"orders":["age; DROP TABLE test_models"]}`))
+	assert.Error(t, err)
+	// 未知 clause
+	_, err = ParseCriteriaJSON([]byte(`{"conditions":[{"clause":"foo","query":"age = 1"}]}`))
+	assert.Error(t, err)
+	// 条件组的子条件不能设置 clause
+	_, err = ParseCriteriaJSON([]byte(`{"conditions":[{"logic":"or","children":[{"clause":"having","query":"age = 1"}]}]}`))
+	assert.Error(t, err)
+	// 空条件
+	_, err = ParseCriteriaJSON([]byte(`{"conditions":[{}]}`))
+	assert.Error(t, err)
+	_, err = ParseCriteriaJSON([]byte(`not json`))
+	assert.Error(t, err)
+
+	// 包含闭包和子查询的 Criteria 不能序列化
+	_, err = json.Marshal(NewCriteria().ScopeClosure(func(tx *gorm.DB) *gorm.DB { return tx }))
+	assert.Error(t, err)
+	_, err = json.Marshal(NewCriteria().WhereIn("id", gorm.Expr("SELECT 1")))
+	assert.Error(t, err)
+}
+
+func TestCriteria_WhereHelpersConditions(t *testing.T) {
+	c := NewCriteria().
+		WhereGt("age", 1).
+		WhereIn("status", []string{"a"}).
+		WhereContains("name", "foo").
+		WhereIsNull("email").
+		Where("score = ?", 1)
+	assert.Equal(t, []Condition{
+		{Field: "age", Operator: "gt", Value: 1},
+		{Field: "status", Operator: "in", Value: []string{"a"}},
+		{Field: "name", Operator: "like", Value: "foo"},
+		{Field: "email", Operator: "isnull"},
+		{Clause: "where", Query: "score = ?", Args: []any{1}},
+	}, c.conditions)
+	assert.False(t, c.opaque)
+}
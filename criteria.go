@@ -9,17 +9,25 @@ import (
 	"github.com/spf13/cast"
 	"golang.org/x/exp/slices"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
 const (
 	criteriaLike    = "like"
 	criteriaLLike   = "llike"
 	criteriaRLike   = "rlike"
+	criteriaIn      = "in"
+	criteriaNotIn   = "notin"
+	criteriaIsNull  = "isnull"
+	criteriaNotNull = "notnull"
+	criteriaBetween = "between"
 	criteriaSort    = "sort"
 	criteriaPerPage = "per_page"
 	criteriaPage    = "page"
 	criteriaOffset  = "offset"
 	criteriaLimit   = "limit"
+	criteriaCursor  = "cursor"
 )
 
 type conditionSpec struct {
@@ -36,6 +44,10 @@ type Criteria struct {
 	offset        int
 	group         string
 	page          int
+	cursor        string
+	// conditions 记录可序列化的条件树，opaque 表示包含无法序列化的条件
+	conditions []Condition
+	opaque     bool
 }
 
 var conditionMapping = map[string]string{
@@ -45,16 +57,41 @@ var conditionMapping = map[string]string{
 	"gte": ">=",
 	"lt":  "<",
 	"lte": "<=",
-	"in":  "IN",
 }
 
-var valueStringOperator = []string{criteriaLike, criteriaLLike, criteriaRLike, criteriaSort}
+var sliceConditionMapping = map[string]string{
+	criteriaIn:    "IN",
+	criteriaNotIn: "NOT IN",
+}
+
+var nullConditionMapping = map[string]string{
+	criteriaIsNull:  "IS NULL",
+	criteriaNotNull: "IS NOT NULL",
+}
+
+// inverseNullOperator 值为 false 时 isnull/notnull 使用的相反 operator
+var inverseNullOperator = map[string]string{
+	criteriaIsNull:  criteriaNotNull,
+	criteriaNotNull: criteriaIsNull,
+}
+
+var valueStringOperator = []string{criteriaLike, criteriaLLike, criteriaRLike}
+
+var controlOperator = []string{criteriaSort, criteriaPerPage, criteriaPage, criteriaOffset, criteriaLimit, criteriaCursor}
 
 func NewCriteria() *Criteria {
 	return &Criteria{}
 }
 
+// ExtractCriteria 根据结构体的 criteria tag 生成 Criteria。
+// -:sort 字段可以通过 sortable tag 限制允许排序的字段，例如 `criteria:"-:sort" sortable:"name,created_at"`，
+// 使用 GormStore.ExtractCriteria 时还会校验过滤和排序字段是模型的字段。
 func ExtractCriteria(source any) (*Criteria, error) {
+	return extractCriteria(source, nil)
+}
+
+// extractCriteria resolveField 不为空时用于校验过滤字段和没有 sortable tag 的排序字段，并返回字段对应的列名
+func extractCriteria(source any, resolveField func(field string) (string, error)) (*Criteria, error) {
 	if source == nil {
 		return nil, errors.New("empty source")
 	}
@@ -122,82 +159,159 @@ func ExtractCriteria(source any) (*Criteria, error) {
 				return nil, err
 			}
 			criteria.Limit(value)
+		case criteriaCursor:
+			value, err := cast.ToStringE(fieldValue)
+			if err != nil {
+				return nil, err
+			}
+			criteria.Cursor(value)
 		case criteriaSort:
 			value, err := cast.ToStringE(fieldValue)
 			if err != nil {
 				return nil, err
 			}
+			var sortable []string
+			if tag := sf.Tag.Get("sortable"); tag != "" {
+				for _, field := range strings.Split(tag, ",") {
+					sortable = append(sortable, strings.TrimSpace(field))
+				}
+			}
 			orders := strings.Split(value, ",")
 			for _, order := range orders {
-				criteria.Order(strings.TrimSpace(strings.TrimRight(order, "+-")), strings.HasSuffix(order, "-"))
-			}
-		}
-		fields := strings.Split(criteriaOptions[0], ",")
-		if len(fields) > 1 {
-			groupSpec := make(groupConditionSpec, 0, len(fields))
-			for _, field := range fields {
-				wc, err := criteria.buildConditionSpec(criteriaOperator, field, fieldValue)
-				if err != nil {
-					return nil, err
+				order = strings.TrimSpace(order)
+				field := strings.TrimSpace(strings.TrimRight(order, "+-"))
+				if field == "" {
+					continue
 				}
-				if wc.query != "" {
-					groupSpec = append(groupSpec, wc)
+				if field, err = resolveSortField(field, sortable, resolveField); err != nil {
+					return nil, err
 				}
+				criteria.Order(field, strings.HasSuffix(order, "-"))
+			}
+		}
+		// 分页和排序不生成查询条件
+		if slices.Contains(controlOperator, criteriaOperator) {
+			continue
+		}
+		// in/notin 的值为空时不生成查询条件
+		if _, ok := sliceConditionMapping[criteriaOperator]; ok {
+			values, err := toSliceValues(fieldValue)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) == 0 {
+				continue
 			}
-			if len(groupSpec) > 0 {
-				criteria.GroupOr(groupSpec)
+		}
+		fields := strings.Split(criteriaOptions[0], ",")
+		for i, field := range fields {
+			fields[i] = strings.TrimSpace(field)
+			if resolveField == nil {
+				continue
 			}
-		} else {
-			wc, err := criteria.buildConditionSpec(criteriaOperator, criteriaOptions[0], fieldValue)
+			column, err := resolveField(fields[i])
 			if err != nil {
 				return nil, err
 			}
-			if wc.query != "" {
-				criteria.Where(wc.query, wc.args...)
+			fields[i] = column
+		}
+		cond := Condition{Field: fields[0], Operator: criteriaOperator, Value: fieldValue}
+		if len(fields) > 1 {
+			cond = Condition{Logic: logicOr, Children: make([]Condition, 0, len(fields))}
+			for _, field := range fields {
+				cond.Children = append(cond.Children, Condition{Field: field, Operator: criteriaOperator, Value: fieldValue})
 			}
 		}
+		if err := criteria.addCondition(cond); err != nil {
+			return nil, err
+		}
 	}
 	return &criteria, nil
 }
 
+// resolveSortField 有 sortable 时排序字段必须在其中，否则排序字段必须是合法的字段名
+func resolveSortField(field string, sortable []string, resolveField func(field string) (string, error)) (string, error) {
+	if sortable != nil {
+		if !slices.Contains(sortable, field) {
+			return "", fmt.Errorf("criteria sort field %s is not sortable, allowed: %s", field, strings.Join(sortable, ","))
+		}
+		return field, nil
+	}
+	if !identifierRegexp.MatchString(field) {
+		return "", fmt.Errorf("invalid criteria sort field: %s", field)
+	}
+	if resolveField != nil {
+		return resolveField(field)
+	}
+	return field, nil
+}
+
 func (c *Criteria) Where(query any, values ...any) *Criteria {
-	return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+	return c.rawCondition(conditionWhere, query, values, func(tx *gorm.DB) *gorm.DB {
 		return tx.Where(query, values...)
 	})
 }
 
 func (c *Criteria) WhereGt(field string, value any) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" > ?", value)
+	return c.fieldCondition("gt", field, value)
 }
 
 func (c *Criteria) WhereGte(field string, value any) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" >= ?", value)
+	return c.fieldCondition("gte", field, value)
 }
 
 func (c *Criteria) WhereLte(field string, value any) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" <= ?", value)
+	return c.fieldCondition("lte", field, value)
 }
 
 func (c *Criteria) WhereLt(field string, value any) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" < ?", value)
+	return c.fieldCondition("lt", field, value)
 }
 
 func (c *Criteria) WhereNeq(field string, value any) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" <> ?", value)
+	return c.fieldCondition("neq", field, value)
 }
 
-// 优化 buildConditionSpec 方法，使用 QuoteReservedWord 保护字段名
-func (c *Criteria) buildConditionSpec(criteriaOperator string, field string, fieldValue any) (cond conditionSpec, err error) {
-	field = QuoteReservedWord(field)
+// buildFieldCondition 根据 operator 构建查询条件，字段名使用 quote 转义
+func buildFieldCondition(quote func(string) string, criteriaOperator string, field string, fieldValue any) (cond conditionSpec, err error) {
+	field = quote(field)
 	cond = conditionSpec{}
 	if operator, ok := conditionMapping[criteriaOperator]; ok {
 		cond.query = fmt.Sprintf("%s %s ?", field, operator)
 		cond.args = []any{fieldValue}
+	} else if operator, ok = sliceConditionMapping[criteriaOperator]; ok {
+		var values []any
+		values, err = toSliceValues(fieldValue)
+		if err != nil {
+			return
+		}
+		cond.query = fmt.Sprintf("%s %s ?", field, operator)
+		cond.args = []any{values}
+	} else if operator, ok = nullConditionMapping[criteriaOperator]; ok {
+		// 值作为开关，为 false 时使用相反的条件，nil 视为 true
+		isSet := true
+		if fieldValue != nil {
+			if isSet, err = cast.ToBoolE(fieldValue); err != nil {
+				err = fmt.Errorf("criteria %s value of %s must be a bool: %w", criteriaOperator, field, err)
+				return
+			}
+		}
+		if !isSet {
+			operator = nullConditionMapping[inverseNullOperator[criteriaOperator]]
+		}
+		cond.query = fmt.Sprintf("%s %s", field, operator)
+	} else if criteriaOperator == criteriaBetween {
+		var values []any
+		values, err = toSliceValues(fieldValue)
+		if err != nil {
+			return
+		}
+		if len(values) != 2 {
+			err = fmt.Errorf("criteria between value of %s must have 2 elements, got %d", field, len(values))
+			return
+		}
+		cond.query = fmt.Sprintf("%s BETWEEN ? AND ?", field)
+		cond.args = values
 	} else if slices.Contains(valueStringOperator, criteriaOperator) {
 		var value string
 		value, err = cast.ToStringE(fieldValue)
@@ -205,13 +319,38 @@ func (c *Criteria) buildConditionSpec(criteriaOperator string, field string, fie
 			return
 		}
 		cond = buildLikeCondition(field, value, criteriaOperator)
+	} else {
+		err = fmt.Errorf("unknown criteria operator: %s", criteriaOperator)
 	}
 	return
 }
 
-// 添加一个辅助函数，用于构建 LIKE 条件，减少代码重复
+// toSliceValues 将 slice/array 或英文逗号分隔的字符串转换为 []any
+func toSliceValues(fieldValue any) ([]any, error) {
+	if value, ok := fieldValue.(string); ok {
+		parts := strings.Split(value, ",")
+		values := make([]any, 0, len(parts))
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part != "" {
+				values = append(values, part)
+			}
+		}
+		return values, nil
+	}
+	rv := reflect.ValueOf(fieldValue)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("criteria value must be a slice or comma separated string, got %T", fieldValue)
+	}
+	values := make([]any, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		values = append(values, rv.Index(i).Interface())
+	}
+	return values, nil
+}
+
+// 添加一个辅助函数，用于构建 LIKE 条件，减少代码重复，field 需要已经转义
 func buildLikeCondition(field, value, likeType string) (cond conditionSpec) {
-	field = QuoteReservedWord(field)
 	cond.query = fmt.Sprintf("%s like ?", field)
 
 	switch likeType {
@@ -230,68 +369,139 @@ func (c *Criteria) GroupOr(group groupConditionSpec) *Criteria {
 	if len(group) == 0 {
 		return c // 如果组为空，直接返回
 	}
-	return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
-		sub := tx.Session(&gorm.Session{NewDB: true})
-		for _, cond := range group {
-			sub = sub.Or(cond.query, cond.args...)
+	children := make([]Condition, 0, len(group))
+	for _, cond := range group {
+		children = append(children, Condition{Query: cond.query, Args: cond.args})
+	}
+	if !serializable([]any{children}) {
+		return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+			sub := tx.Session(&gorm.Session{NewDB: true})
+			for _, cond := range group {
+				sub = sub.Or(cond.query, cond.args...)
+			}
+			return tx.Where(sub)
+		})
+	}
+	return c.condition(Condition{Logic: logicOr, Children: children})
+}
+
+// AndGroup 添加用括号包裹的条件组，与前面的条件使用 AND 连接。
+// fn 中的 Where 类条件使用 AND 连接，OrWhere 和 OrGroup 使用 OR 连接，可以任意嵌套。
+// 条件组只支持 Where 类条件和命名 scope，fn 中设置的排序、分页会被忽略，使用 Joins、Having、AddPreload 时查询返回错误。
+func (c *Criteria) AndGroup(fn func(*Criteria)) *Criteria {
+	return c.addGroup(conditionWhere, fn)
+}
+
+// OrGroup 添加用括号包裹的条件组，与前面的条件使用 OR 连接，用法同 AndGroup
+func (c *Criteria) OrGroup(fn func(*Criteria)) *Criteria {
+	return c.addGroup(conditionOr, fn)
+}
+
+func (c *Criteria) addGroup(clauseName string, fn func(*Criteria)) *Criteria {
+	sub := NewCriteria()
+	fn(sub)
+	if len(sub.scopeClosures) == 0 {
+		return c
+	}
+	for _, cond := range sub.conditions {
+		switch cond.Clause {
+		case "", conditionWhere, conditionOr, conditionNot, conditionScope:
+		default:
+			return c.addError(fmt.Errorf("criteria group child can not use clause: %s", cond.Clause))
+		}
+	}
+	if !sub.opaque {
+		return c.condition(Condition{Clause: clauseName, Children: sub.conditions})
+	}
+	// 包含无法序列化的条件时交给 gorm 组合条件组
+	closures := sub.scopeClosures
+	c.scopeClosures = append(c.scopeClosures, func(tx *gorm.DB) *gorm.DB {
+		if tx.Error != nil {
+			return tx
+		}
+		group := groupSession(tx)
+		for _, closure := range closures {
+			group = closure(group)
 		}
-		return tx.Where(sub)
+		// 条件组的错误不会传递到外层查询
+		if group.Error != nil {
+			_ = tx.AddError(group.Error)
+			return tx
+		}
+		if clauseName == conditionOr {
+			return tx.Or(group)
+		}
+		return tx.Where(group)
 	})
+	c.opaque = true
+	return c
 }
 
 func (c *Criteria) WhereNot(query any, values ...any) *Criteria {
-	return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+	return c.rawCondition(conditionNot, query, values, func(tx *gorm.DB) *gorm.DB {
 		return tx.Not(query, values...)
 	})
 }
 
 func (c *Criteria) WhereIsNull(field string) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field + " IS NULL")
+	return c.fieldCondition(criteriaIsNull, field, nil)
 }
 
 func (c *Criteria) WhereNotNull(field string) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field + " IS NOT NULL")
+	return c.fieldCondition(criteriaNotNull, field, nil)
 }
 
 func (c *Criteria) WhereIn(field string, values any) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" IN ?", values)
+	return c.whereSlice(criteriaIn, field, values)
 }
 
 func (c *Criteria) WhereNotIn(field string, values any) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" NOT IN ?", values)
+	return c.whereSlice(criteriaNotIn, field, values)
+}
+
+// whereSlice 值为 slice 时生成字段条件，单个值作为只有一个元素的 slice，子查询等表达式按原样传给 gorm
+func (c *Criteria) whereSlice(operator, field string, values any) *Criteria {
+	if !serializable([]any{values}) {
+		c.scopeClosures = append(c.scopeClosures, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(fmt.Sprintf("%s %s ?", QuoteIdentifier(tx, field), sliceConditionMapping[operator]), values)
+		})
+		c.opaque = true
+		return c
+	}
+	if _, ok := values.(string); ok {
+		return c.fieldCondition(operator, field, []any{values})
+	}
+	if _, err := toSliceValues(values); err != nil {
+		return c.fieldCondition(operator, field, []any{values})
+	}
+	return c.fieldCondition(operator, field, values)
 }
 
 func (c *Criteria) WhereStartWith(field string, value string) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" LIKE ?", value+"%")
+	return c.fieldCondition(criteriaRLike, field, value)
 }
 
 func (c *Criteria) WhereEndWith(field string, value string) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" LIKE ?", "%"+value)
+	return c.fieldCondition(criteriaLLike, field, value)
 }
 
 func (c *Criteria) WhereContains(field string, value string) *Criteria {
-	field = QuoteReservedWord(field)
-	return c.Where(field+" LIKE ?", "%"+value+"%")
+	return c.fieldCondition(criteriaLike, field, value)
 }
 
 func (c *Criteria) WhereBetween(field string, start, end any) *Criteria {
-	return c.Where(field+" BETWEEN ? AND ?", start, end)
+	return c.fieldCondition(criteriaBetween, field, []any{start, end})
 }
 
 func (c *Criteria) OrWhere(query any, values ...any) *Criteria {
-	return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+	return c.rawCondition(conditionOr, query, values, func(tx *gorm.DB) *gorm.DB {
 		return tx.Or(query, values...)
 	})
 }
 
+// Order 添加排序，字段名中的保留字在查询时按数据库的方言转义
 func (c *Criteria) Order(value string, isDescending bool) *Criteria {
-	orderStatement := QuoteReservedWord(value)
+	orderStatement := value
 	if isDescending {
 		orderStatement = fmt.Sprintf("%s DESC", orderStatement)
 	}
@@ -333,34 +543,186 @@ func (c *Criteria) PerPage(perPage int) *Criteria {
 	return c
 }
 
+// Cursor 设置游标分页的游标，配合 GormStore.CursorPaginate 使用
+func (c *Criteria) Cursor(cursor string) *Criteria {
+	c.cursor = cursor
+	return c
+}
+
 func (c *Criteria) Group(query string) *Criteria {
 	c.group = query
 	return c
 }
 
 func (c *Criteria) Having(query any, values ...any) *Criteria {
-	return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+	return c.rawCondition(conditionHaving, query, values, func(tx *gorm.DB) *gorm.DB {
 		return tx.Having(query, values...)
 	})
 }
 
 func (c *Criteria) Joins(query string, values ...any) *Criteria {
-	return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+	return c.rawCondition(conditionJoins, query, values, func(tx *gorm.DB) *gorm.DB {
 		return tx.Joins(query, values...)
 	})
 }
 
 func (c *Criteria) AddPreload(name string, args ...any) *Criteria {
-	return c.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+	return c.rawCondition(conditionPreload, name, args, func(tx *gorm.DB) *gorm.DB {
 		return tx.Preload(name, args...)
 	})
 }
 
+// ScopeClosure 添加自定义的 gorm 闭包，包含闭包的 Criteria 不会被缓存
 func (c *Criteria) ScopeClosure(closure gormClosure) *Criteria {
 	c.scopeClosures = append(c.scopeClosures, closure)
+	c.opaque = true
+	return c
+}
+
+// Conditions 返回 Where* 方法、ExtractCriteria 等生成的条件，ScopeClosure 和子查询等条件不包含在内
+func (c *Criteria) Conditions() []Condition {
+	return append([]Condition(nil), c.conditions...)
+}
+
+// Clone 复制 Criteria，返回的实例与 c 不共享任何 slice，c 为 nil 时返回空的 Criteria
+func (c *Criteria) Clone() *Criteria {
+	if c == nil {
+		return NewCriteria()
+	}
+	nc := *c
+	nc.scopeClosures = append([]gormClosure(nil), c.scopeClosures...)
+	nc.orders = append([]string(nil), c.orders...)
+	nc.conditions = append([]Condition(nil), c.conditions...)
+	return &nc
+}
+
+// Merge 将 other 的条件和排序追加到 c 之后，other 设置了分页、分组、游标时覆盖 c 的设置。
+// other 的 Where 类条件作为一个整体与 c 的条件使用 AND 连接，other 中的 OR 条件不会绕过 c 的条件。
+func (c *Criteria) Merge(other *Criteria) *Criteria {
+	if other == nil {
+		return c
+	}
+	other = other.Clone()
+	if other.opaque {
+		c.mergeClosures(other.scopeClosures)
+	} else {
+		c.mergeConditions(other.conditions)
+	}
+	c.orders = append(c.orders, other.orders...)
+	if other.limit > 0 {
+		c.limit = other.limit
+	}
+	if other.offset > 0 {
+		c.offset = other.offset
+	}
+	if other.page > 0 {
+		c.page = other.page
+	}
+	if other.group != "" {
+		c.group = other.group
+	}
+	if other.cursor != "" {
+		c.cursor = other.cursor
+	}
 	return c
 }
 
+// mergeConditions 合并可以序列化的条件，多个 Where 类条件或包含 OR 时合并为一个条件组
+func (c *Criteria) mergeConditions(conditions []Condition) {
+	var wheres, others []Condition
+	grouped := false
+	for _, cond := range conditions {
+		switch cond.Clause {
+		case "", conditionWhere, conditionNot, conditionScope:
+		case conditionOr:
+			grouped = true
+		default:
+			others = append(others, cond)
+			continue
+		}
+		wheres = append(wheres, cond)
+	}
+	if grouped || len(wheres) > 1 {
+		wheres = []Condition{{Clause: conditionWhere, Children: wheres}}
+	}
+	for _, cond := range append(wheres, others...) {
+		c.condition(cond)
+	}
+}
+
+// mergeClosures 合并包含无法序列化条件的闭包，闭包添加的 WHERE 条件包含 OR 时用括号包裹
+func (c *Criteria) mergeClosures(closures []gormClosure) {
+	c.scopeClosures = append(c.scopeClosures, func(tx *gorm.DB) *gorm.DB {
+		from := 0
+		if where, ok := tx.Statement.Clauses["WHERE"].Expression.(clause.Where); ok {
+			from = len(where.Exprs)
+		}
+		for _, closure := range closures {
+			tx = closure(tx)
+		}
+		return tx.Clauses(groupWhere{from: from})
+	})
+	c.opaque = true
+}
+
+// ToSQL 返回 Criteria 作用在 model 上生成的查询语句和参数，使用 DryRun 模式不会执行查询
+func (c *Criteria) ToSQL(db *gorm.DB, model any) (string, []any, error) {
+	value := reflect.ValueOf(model)
+	if !value.IsValid() {
+		return "", nil, errors.New("criteria to sql requires a model")
+	}
+	dest := reflect.New(reflect.Indirect(value).Type()).Interface()
+	tx := db.Session(&gorm.Session{DryRun: true, NewDB: true}).Model(dest)
+	// WhereHas 等关联条件需要解析 model 的关联关系
+	tx = tx.Set(schemaSettingKey, func() (*schema.Schema, error) {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(dest); err != nil {
+			return nil, err
+		}
+		return stmt.Schema, nil
+	})
+	tx = c.apply(tx).Find(dest)
+	if tx.Error != nil {
+		return "", nil, tx.Error
+	}
+	return tx.Statement.SQL.String(), tx.Statement.Vars, nil
+}
+
+// apply 将分页、分组、排序和条件作用在 db 上
+func (c *Criteria) apply(db *gorm.DB) *gorm.DB {
+	if c.GetOffset() > 0 {
+		db = db.Offset(c.GetOffset())
+	}
+	// 有 offset 一定要有 limit
+	if c.limit > 0 || c.GetOffset() > 0 {
+		db = db.Limit(c.limit)
+	}
+	if c.group != "" {
+		db = db.Group(c.group)
+	}
+	for _, item := range c.orders {
+		db = db.Order(quoteOrder(db, item))
+	}
+	for _, closure := range c.scopeClosures {
+		db = closure(db)
+	}
+	return db
+}
+
+// quoteOrder 转义排序语句中的字段名，不是字段名的排序表达式保持不变
+func quoteOrder(db *gorm.DB, order string) string {
+	column, direction := order, ""
+	if i := strings.LastIndex(order, " "); i > 0 {
+		if upper := strings.ToUpper(order[i+1:]); upper == "ASC" || upper == "DESC" {
+			column, direction = order[:i], order[i:]
+		}
+	}
+	if !identifierRegexp.MatchString(column) {
+		return order
+	}
+	return QuoteIdentifier(db, column) + direction
+}
+
 func (c *Criteria) GetPage() int {
 	return c.page
 }
@@ -369,6 +731,10 @@ func (c *Criteria) GetPerPage() int {
 	return c.limit
 }
 
+func (c *Criteria) GetCursor() string {
+	return c.cursor
+}
+
 func (c *Criteria) GetOffset() int {
 	if c.offset > 0 {
 		return c.offset
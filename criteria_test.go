@@ -1,9 +1,12 @@
 package storeit
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 type testCriteriaStruct struct {
@@ -154,39 +157,99 @@ func TestCriteria_unsetOrderAndLimit(t *testing.T) {
 	assert.Equal(t, 0, c.offset)
 }
 
-func TestCriteria_buildConditionSpec(t *testing.T) {
-	c := NewCriteria()
+func TestCriteria_buildFieldCondition(t *testing.T) {
 	// eq
-	cond, err := c.buildConditionSpec("eq", "name", "foo")
+	cond, err := buildFieldCondition(QuoteReservedWord, "eq", "name", "foo")
 	assert.NoError(t, err)
 	assert.Equal(t, "name = ?", cond.query)
 	assert.Equal(t, []any{"foo"}, cond.args)
 
 	// gt
-	cond, err = c.buildConditionSpec("gt", "age", 18)
+	cond, err = buildFieldCondition(QuoteReservedWord, "gt", "age", 18)
 	assert.NoError(t, err)
 	assert.Equal(t, "age > ?", cond.query)
 
 	// like
-	cond, err = c.buildConditionSpec("like", "email", "bar")
+	cond, err = buildFieldCondition(QuoteReservedWord, "like", "email", "bar")
 	assert.NoError(t, err)
 	assert.Equal(t, "email like ?", cond.query)
 	assert.Equal(t, []any{"%bar%"}, cond.args)
 
 	// llike
-	cond, err = c.buildConditionSpec("llike", "email", "bar")
+	cond, err = buildFieldCondition(QuoteReservedWord, "llike", "email", "bar")
 	assert.NoError(t, err)
 	assert.Equal(t, []any{"%bar"}, cond.args)
 
 	// rlike
-	cond, err = c.buildConditionSpec("rlike", "email", "bar")
+	cond, err = buildFieldCondition(QuoteReservedWord, "rlike", "email", "bar")
 	assert.NoError(t, err)
 	assert.Equal(t, []any{"bar%"}, cond.args)
 
-	// unknown
-	cond, err = c.buildConditionSpec("unknown", "foo", "bar")
+	// in
+	cond, err = buildFieldCondition(QuoteReservedWord, "in", "status", []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "status IN ?", cond.query)
+	assert.Equal(t, []any{[]any{"a", "b"}}, cond.args)
+
+	// in 逗号分隔字符串
+	cond, err = buildFieldCondition(QuoteReservedWord, "in", "status", "a, b,")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{[]any{"a", "b"}}, cond.args)
+
+	// notin
+	cond, err = buildFieldCondition(QuoteReservedWord, "notin", "status", []int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "status NOT IN ?", cond.query)
+	assert.Equal(t, []any{[]any{1, 2}}, cond.args)
+
+	// in 非 slice
+	_, err = buildFieldCondition(QuoteReservedWord, "in", "status", 1)
+	assert.Error(t, err)
+
+	// isnull / notnull
+	cond, err = buildFieldCondition(QuoteReservedWord, "isnull", "email", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "email IS NULL", cond.query)
+	assert.Empty(t, cond.args)
+	cond, err = buildFieldCondition(QuoteReservedWord, "notnull", "email", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "email IS NOT NULL", cond.query)
+	cond, err = buildFieldCondition(QuoteReservedWord, "isnull", "email", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "email IS NULL", cond.query)
+	// 值为 false 时使用相反的条件
+	cond, err = buildFieldCondition(QuoteReservedWord, "isnull", "email", "false")
+	assert.NoError(t, err)
+	assert.Equal(t, "email IS NOT NULL", cond.query)
+	no := false
+	cond, err = buildFieldCondition(QuoteReservedWord, "notnull", "email", &no)
+	assert.NoError(t, err)
+	assert.Equal(t, "email IS NULL", cond.query)
+	_, err = buildFieldCondition(QuoteReservedWord, "notnull", "email", "maybe")
+	assert.Error(t, err)
+
+	// between
+	cond, err = buildFieldCondition(QuoteReservedWord, "between", "age", []int{1, 10})
+	assert.NoError(t, err)
+	assert.Equal(t, "age BETWEEN ? AND ?", cond.query)
+	assert.Equal(t, []any{1, 10}, cond.args)
+	cond, err = buildFieldCondition(QuoteReservedWord, "between", "age", "1,10")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"1", "10"}, cond.args)
+	_, err = buildFieldCondition(QuoteReservedWord, "between", "age", []int{1})
+	assert.Error(t, err)
+
+	// 字段名使用 quote 转义
+	cond, err = buildFieldCondition(QuoteReservedWord, "eq", "order", 1)
 	assert.NoError(t, err)
-	assert.Empty(t, cond.query)
+	assert.Equal(t, "`order` = ?", cond.query)
+	cond, err = buildFieldCondition(func(field string) string { return `"` + field + `"` }, "gte", "age", 18)
+	assert.NoError(t, err)
+	assert.Equal(t, `"age" >= ?`, cond.query)
+
+	// unknown
+	_, err = buildFieldCondition(QuoteReservedWord, "unknown", "foo", "bar")
+	assert.Error(t, err)
 }
 
 func TestBuildLikeCondition(t *testing.T) {
@@ -236,6 +299,14 @@ func TestCriteria_TagError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCriteria_UnknownOperator(t *testing.T) {
+	type S struct {
+		Foo string `criteria:"foo:unknown"`
+	}
+	_, err := ExtractCriteria(S{Foo: "bar"})
+	assert.Error(t, err)
+}
+
 func TestCriteria_PageLessThanOne(t *testing.T) {
 	c := NewCriteria().Page(-1)
 	assert.Equal(t, 1, c.page)
@@ -255,5 +326,223 @@ func TestCriteria_WhereBetween(t *testing.T) {
 func TestCriteria_OrderReservedWord(t *testing.T) {
 	c := NewCriteria()
 	c.Order("order", false)
-	assert.Contains(t, c.orders[0], "`order`")
+	// 保留字在查询时按数据库的方言转义
+	sql, _, err := c.ToSQL(setupTestDB(t), TestModel{})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "ORDER BY `order`")
+}
+
+func TestCriteria_QuoteByDialect(t *testing.T) {
+	db := setupPostgresLikeDB(t)
+	c := NewCriteria().
+		WhereBetween("order", 1, 10).
+		WhereIn("user", []int{1}).
+		WhereNeq("users.group", "a").
+		OrderDesc("user").
+		OrderAsc("LENGTH(name)")
+	sql, args, err := c.ToSQL(db, TestModel{})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, `"order" BETWEEN ? AND ?`)
+	assert.Contains(t, sql, `"user" IN (?)`)
+	assert.Contains(t, sql, `users."group" <> ?`)
+	assert.Contains(t, sql, `ORDER BY "user" DESC,LENGTH(name)`)
+	assert.Equal(t, []any{1, 10, 1, "a"}, args)
+}
+
+func TestCriteria_ToSQL(t *testing.T) {
+	db := setupTestDB(t)
+	c := NewCriteria().WhereGte("age", 18).WhereIn("name", []string{"a", "b"}).OrderDesc("age").Limit(10).Offset(20)
+	sql, args, err := c.ToSQL(db, TestModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `test_models` WHERE age >= ? AND name IN (?,?) AND `test_models`.`deleted_at` IS NULL ORDER BY age DESC LIMIT 10 OFFSET 20", sql)
+	assert.Equal(t, []any{18, "a", "b"}, args)
+
+	// 指针和 slice 指针
+	sql2, _, err := c.ToSQL(db, &[]TestModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, sql2)
+
+	// DryRun 不执行查询
+	var count int64
+	assert.NoError(t, db.Model(&TestModel{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	_, _, err = c.ToSQL(db, nil)
+	assert.Error(t, err)
+}
+
+func TestCriteria_Conditions(t *testing.T) {
+	type S struct {
+		Name string `criteria:"name:rlike"`
+		Age  int    `criteria:"age:gte"`
+	}
+	c, err := ExtractCriteria(S{Name: "foo", Age: 18})
+	assert.NoError(t, err)
+	c.WhereNeq("status", "deleted").ScopeClosure(func(tx *gorm.DB) *gorm.DB { return tx })
+
+	conditions := c.Conditions()
+	assert.Equal(t, []Condition{
+		{Field: "name", Operator: "rlike", Value: "foo"},
+		{Field: "age", Operator: "gte", Value: 18},
+		{Field: "status", Operator: "neq", Value: "deleted"},
+	}, conditions)
+
+	// 修改返回值不影响 Criteria
+	conditions[0].Field = "email"
+	assert.Equal(t, "name", c.Conditions()[0].Field)
+}
+
+func TestCriteria_ExtractSortable(t *testing.T) {
+	type S struct {
+		Sort string `criteria:"-:sort" sortable:"name, created_at"`
+	}
+	c, err := ExtractCriteria(S{Sort: "name-,created_at+,"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name DESC", "created_at"}, c.orders)
+
+	_, err = ExtractCriteria(S{Sort: "password-"})
+	assert.ErrorContains(t, err, "password")
+	assert.ErrorContains(t, err, "name,created_at")
+
+	// 没有 sortable 时排序字段必须是合法的字段名
+	type T struct {
+		Sort string `criteria:"-:sort"`
+	}
+	_, err = ExtractCriteria(T{Sort: "name;DROP TABLE users-"})
+	assert.Error(t, err)
+	c, err = ExtractCriteria(T{Sort: "users.name-"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users.name DESC"}, c.orders)
+}
+
+func TestCriteria_AndOrGroup(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+	models := []TestModel{
+		{Name: "Alice", Age: 20, Score: 60},
+		{Name: "Bob", Age: 30, Score: 90},
+		{Name: "Carol", Age: 40, Score: 70},
+		{Name: "Dave", Age: 50, Score: 95},
+	}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+
+	// (age < 25 AND score < 70) OR (age > 25 AND (score > 92 OR name = 'Bob'))
+	c := NewCriteria().
+		AndGroup(func(g *Criteria) {
+			g.WhereLt("age", 25).WhereLt("score", 70)
+		}).
+		OrGroup(func(g *Criteria) {
+			g.WhereGt("age", 25).AndGroup(func(g *Criteria) {
+				g.WhereGt("score", 92).OrWhere("name = ?", "Bob")
+			})
+		}).
+		OrderAsc("age")
+	sql, args, err := c.ToSQL(db, TestModel{})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "WHERE (((age < ? AND score < ?)) OR ((age > ? AND (score > ? OR (name = ?))))) AND")
+	assert.Equal(t, []any{25, 70, 25, 92, "Bob"}, args)
+
+	found, err := store.Find(ctx, c)
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+	assert.Equal(t, "Alice", found[0].Name)
+	assert.Equal(t, "Bob", found[1].Name)
+	assert.Equal(t, "Dave", found[2].Name)
+
+	// 条件组可以序列化
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	parsed, err := ParseCriteriaJSON(data)
+	assert.NoError(t, err)
+	again, err := store.Find(ctx, parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, found, again)
+
+	// 空条件组被忽略
+	c = NewCriteria().WhereGt("age", 45).OrGroup(func(*Criteria) {})
+	found, err = store.Find(ctx, c)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// 包含闭包的条件组交给 gorm 组合
+	c = NewCriteria().WhereGt("age", 45).OrGroup(func(g *Criteria) {
+		g.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("name = ?", "Alice")
+		}).WhereLt("score", 70)
+	})
+	found, err = store.Find(ctx, c.OrderAsc("age"))
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, "Alice", found[0].Name)
+	assert.Equal(t, "Dave", found[1].Name)
+
+	// 条件组中不能使用 Joins、Having 等
+	_, err = store.Find(ctx, NewCriteria().AndGroup(func(g *Criteria) {
+		g.Joins("LEFT JOIN addresses ON addresses.user_id = test_models.id").WhereGt("age", 25)
+	}))
+	assert.ErrorContains(t, err, "criteria group child can not use clause: joins")
+	_, err = store.Find(ctx, NewCriteria().OrGroup(func(g *Criteria) {
+		g.ScopeClosure(func(tx *gorm.DB) *gorm.DB { return tx }).Having("COUNT(*) > ?", 1)
+	}))
+	assert.ErrorContains(t, err, "criteria group child can not use clause: having")
+}
+
+func TestCriteria_CloneMerge(t *testing.T) {
+	base := NewCriteria().WhereGte("age", 18).OrderDesc("age").Limit(10)
+
+	// 修改副本不影响原来的 Criteria
+	clone := base.Clone()
+	clone.WhereLt("age", 60).OrderAsc("name").Limit(20)
+	assert.Len(t, base.conditions, 1)
+	assert.Len(t, base.scopeClosures, 1)
+	assert.Equal(t, []string{"age DESC"}, base.orders)
+	assert.Equal(t, 10, base.GetLimit())
+	assert.NotNil(t, (*Criteria)(nil).Clone())
+
+	merged := base.Clone().Merge(NewCriteria().WhereIn("name", []string{"a", "b"}).OrderAsc("name").Page(2))
+	assert.Equal(t, []Condition{
+		{Field: "age", Operator: "gte", Value: 18},
+		{Field: "name", Operator: "in", Value: []string{"a", "b"}},
+	}, merged.Conditions())
+	assert.Equal(t, []string{"age DESC", "name"}, merged.orders)
+	assert.Equal(t, 10, merged.GetLimit())
+	assert.Equal(t, 2, merged.GetPage())
+	assert.Len(t, base.conditions, 1)
+	assert.Same(t, merged, merged.Merge(nil))
+
+	// 合并无法序列化的条件
+	merged.Merge(NewCriteria().ScopeClosure(func(tx *gorm.DB) *gorm.DB { return tx }))
+	assert.True(t, merged.opaque)
+	assert.False(t, base.opaque)
+
+	// other 中的 OR 条件作为一个整体与 c 的条件使用 AND 连接
+	db := setupTestDB(t)
+	merged = NewCriteria().Where("age = ?", 1).Clone().Merge(NewCriteria().Where("name = ?", "a").OrWhere("name = ?", "b"))
+	sql, args, err := merged.ToSQL(db, TestModel{})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "WHERE age = ? AND (((name = ?) OR (name = ?))) AND")
+	assert.Equal(t, []any{1, "a", "b"}, args)
+	data, err := json.Marshal(merged)
+	assert.NoError(t, err)
+	parsed, err := ParseCriteriaJSON(data)
+	assert.NoError(t, err)
+	again, _, err := parsed.ToSQL(db, TestModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, sql, again)
+
+	// 包含无法序列化的条件时同样用括号包裹
+	merged = NewCriteria().Where("age = ?", 1).Merge(NewCriteria().Where("name = ?", "a").OrWhere("name = ?", "b").
+		ScopeClosure(func(tx *gorm.DB) *gorm.DB { return tx.Or("name = ?", "c") }))
+	sql, args, err = merged.ToSQL(db, TestModel{})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "WHERE age = ? AND (name = ? OR name = ? OR name = ?) AND")
+	assert.Equal(t, []any{1, "a", "b", "c"}, args)
+
+	store := New[TestModel](db)
+	ctx := context.Background()
+	assert.NoError(t, store.Creates(ctx, []TestModel{{Name: "a", Age: 1}, {Name: "b", Age: 2}}).Error)
+	found, err := store.Find(ctx, NewCriteria().Where("age = ?", 1).Merge(NewCriteria().Where("name = ?", "x").OrWhere("name = ?", "b")))
+	assert.NoError(t, err)
+	assert.Empty(t, found)
 }
@@ -0,0 +1,234 @@
+package storeit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// ErrInvalidCursor 游标无法解码或与当前排序字段不匹配
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type CursorPagination[M any] struct {
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+	Items      []M    `json:"items"`
+}
+
+type cursorPayload struct {
+	Direction string            `json:"d"`
+	Values    []json.RawMessage `json:"v"`
+}
+
+type cursorColumn struct {
+	field      *schema.Field
+	descending bool
+}
+
+// CursorPaginate 基于 keyset 的游标分页，不会执行 COUNT。
+// 排序字段取自 criteria 的 Order，并自动追加主键作为 tiebreaker，排序字段必须是模型上的非 NULL 列。
+// cursor 为空时使用 criteria 上的游标（-:cursor），两者都为空时返回第一页。
+func (r *GormStore[M]) CursorPaginate(ctx context.Context, criteria *Criteria, cursor string) (*CursorPagination[M], error) {
+	c := criteria.Clone()
+	if cursor == "" {
+		cursor = c.GetCursor()
+	}
+	perPage := c.GetPerPage()
+	if perPage < 1 {
+		perPage = 50
+	}
+	s, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := cursorColumns(s, c.orders)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload *cursorPayload
+	if cursor != "" {
+		payload, err = decodeCursor(cursor, len(columns))
+		if err != nil {
+			return nil, err
+		}
+	}
+	backward := payload != nil && payload.Direction == cursorPrev
+
+	c.orders = nil
+	for _, column := range columns {
+		c.Order(column.field.DBName, column.descending != backward)
+	}
+	if payload != nil {
+		values, err := decodeCursorValues(columns, payload.Values)
+		if err != nil {
+			return nil, err
+		}
+		query, args := buildKeysetCondition(columns, values, backward)
+		// 调用方的条件中可能有 OR，keyset 条件需要与全部条件使用 AND 连接
+		c.scopeClosures = append(c.scopeClosures, func(tx *gorm.DB) *gorm.DB {
+			return tx.Clauses(groupWhere{}).Where(query, args...)
+		})
+		c.opaque = true
+	}
+	c.page = 0
+	c.offset = 0
+	c.limit = perPage + 1
+
+	items, err := r.Find(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	hasMore := len(items) > perPage
+	if hasMore {
+		items = items[:perPage]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	pagination := CursorPagination[M]{
+		PerPage: perPage,
+		Items:   items,
+	}
+	if len(items) == 0 {
+		return &pagination, nil
+	}
+	if (backward && hasMore) || (!backward && payload != nil) {
+		pagination.PrevCursor, err = encodeCursor(ctx, cursorPrev, columns, &items[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if backward || hasMore {
+		pagination.NextCursor, err = encodeCursor(ctx, cursorNext, columns, &items[len(items)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &pagination, nil
+}
+
+// cursorColumns 将 criteria 的排序语句解析为模型字段，并追加主键作为 tiebreaker
+func cursorColumns(s *schema.Schema, orders []string) ([]cursorColumn, error) {
+	columns := make([]cursorColumn, 0, len(orders)+len(s.PrimaryFields))
+	ordered := make(map[string]bool, len(orders))
+	for _, order := range orders {
+		name := strings.TrimSpace(order)
+		var descending bool
+		if upper := strings.ToUpper(name); strings.HasSuffix(upper, " DESC") {
+			name, descending = strings.TrimSpace(name[:len(name)-5]), true
+		} else if strings.HasSuffix(upper, " ASC") {
+			name = strings.TrimSpace(name[:len(name)-4])
+		}
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		name = strings.Trim(name, "`\"")
+		field := s.LookUpField(name)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("cursor order column %s not found in %s", name, s.Name)
+		}
+		ordered[field.DBName] = true
+		columns = append(columns, cursorColumn{field: field, descending: descending})
+	}
+	if len(s.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("cursor pagination requires a primary key on %s", s.Name)
+	}
+	// 复合主键的每一列都作为 tiebreaker
+	for _, field := range s.PrimaryFields {
+		if !ordered[field.DBName] {
+			columns = append(columns, cursorColumn{field: field})
+		}
+	}
+	return columns, nil
+}
+
+// buildKeysetCondition 生成 (a > ?) OR (a = ? AND b > ?) ... 形式的 keyset 条件
+func buildKeysetCondition(columns []cursorColumn, values []any, backward bool) (string, []any) {
+	groups := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns)*(len(columns)+1))
+	for i, column := range columns {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, "? = ?")
+			args = append(args, clause.Column{Table: clause.CurrentTable, Name: columns[j].field.DBName}, values[j])
+		}
+		operator := ">"
+		if column.descending != backward {
+			operator = "<"
+		}
+		parts = append(parts, "? "+operator+" ?")
+		args = append(args, clause.Column{Table: clause.CurrentTable, Name: column.field.DBName}, values[i])
+		groups = append(groups, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(groups, " OR ") + ")", args
+}
+
+func encodeCursor(ctx context.Context, direction string, columns []cursorColumn, item any) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(item))
+	payload := cursorPayload{
+		Direction: direction,
+		Values:    make([]json.RawMessage, 0, len(columns)),
+	}
+	for _, column := range columns {
+		value, _ := column.field.ValueOf(ctx, rv)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		payload.Values = append(payload.Values, raw)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string, columns int) (*cursorPayload, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var payload cursorPayload
+	if err = json.Unmarshal(data, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if payload.Direction != cursorNext && payload.Direction != cursorPrev {
+		return nil, ErrInvalidCursor
+	}
+	if len(payload.Values) != columns {
+		return nil, ErrInvalidCursor
+	}
+	return &payload, nil
+}
+
+// decodeCursorValues 按字段类型还原游标中的值，保证 time.Time 等类型绑定正确
+func decodeCursorValues(columns []cursorColumn, raws []json.RawMessage) ([]any, error) {
+	values := make([]any, 0, len(columns))
+	for i, column := range columns {
+		value := reflect.New(column.field.FieldType)
+		if err := json.Unmarshal(raws[i], value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values = append(values, value.Elem().Interface())
+	}
+	return values, nil
+}
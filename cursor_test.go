@@ -0,0 +1,150 @@
+package storeit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormStore_CursorPaginate(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	// 准备测试数据，age 有重复值用于验证主键 tiebreaker
+	var models []TestModel
+	for i := 0; i < 7; i++ {
+		models = append(models, TestModel{
+			Name: fmt.Sprintf("User %d", i),
+			Age:  20 + i/2,
+		})
+	}
+	err := store.Creates(ctx, models).Error
+	assert.NoError(t, err)
+
+	criteria := NewCriteria().OrderDesc("age").PerPage(3)
+
+	// 第一页
+	page, err := store.CursorPaginate(ctx, criteria, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User 6", "User 4", "User 5"}, cursorNames(page.Items))
+	assert.Empty(t, page.PrevCursor)
+	assert.NotEmpty(t, page.NextCursor)
+
+	// 第二页
+	page, err = store.CursorPaginate(ctx, criteria, page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User 2", "User 3", "User 0"}, cursorNames(page.Items))
+	assert.NotEmpty(t, page.PrevCursor)
+	assert.NotEmpty(t, page.NextCursor)
+
+	// 最后一页
+	last, err := store.CursorPaginate(ctx, criteria, page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User 1"}, cursorNames(last.Items))
+	assert.Empty(t, last.NextCursor)
+	assert.NotEmpty(t, last.PrevCursor)
+
+	// 向前翻页
+	prev, err := store.CursorPaginate(ctx, criteria, last.PrevCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User 2", "User 3", "User 0"}, cursorNames(prev.Items))
+	assert.NotEmpty(t, prev.PrevCursor)
+	assert.NotEmpty(t, prev.NextCursor)
+
+	prev, err = store.CursorPaginate(ctx, criteria, prev.PrevCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User 6", "User 4", "User 5"}, cursorNames(prev.Items))
+	assert.Empty(t, prev.PrevCursor)
+
+	// criteria 不应被修改
+	assert.Equal(t, []string{"age DESC"}, criteria.orders)
+	assert.Empty(t, criteria.scopeClosures)
+}
+
+func TestGormStore_CursorPaginate_ExtractCriteria(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	var models []TestModel
+	for i := 0; i < 5; i++ {
+		models = append(models, TestModel{Name: fmt.Sprintf("User %d", i), Age: 20 + i})
+	}
+	err := store.Creates(ctx, models).Error
+	assert.NoError(t, err)
+
+	type req struct {
+		Age     int    `criteria:"age:gt"`
+		PerPage int    `criteria:"-:per_page"`
+		Cursor  string `criteria:"-:cursor"`
+	}
+	criteria, err := ExtractCriteria(req{Age: 20, PerPage: 2})
+	assert.NoError(t, err)
+	page, err := store.CursorPaginate(ctx, criteria, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User 1", "User 2"}, cursorNames(page.Items))
+
+	criteria, err = ExtractCriteria(req{Age: 20, PerPage: 2, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, page.NextCursor, criteria.GetCursor())
+	page, err = store.CursorPaginate(ctx, criteria, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User 3", "User 4"}, cursorNames(page.Items))
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestGormStore_CursorPaginate_OrWhere(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	models := []TestModel{{Name: "b", Age: 1}, {Name: "u0", Age: 2}, {Name: "u1", Age: 3}, {Name: "u2", Age: 4}}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+
+	// keyset 条件与调用方的 OR 条件整体使用 AND 连接，不会重复返回记录
+	criteria := NewCriteria().Where("name = ?", "b").OrWhere("age >= ?", 0).OrderAsc("age").PerPage(2)
+	var names []string
+	cursor := ""
+	for {
+		page, err := store.CursorPaginate(ctx, criteria, cursor)
+		assert.NoError(t, err)
+		names = append(names, cursorNames(page.Items)...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"b", "u0", "u1", "u2"}, names)
+}
+
+func TestGormStore_CursorPaginate_Errors(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	_, err := store.CursorPaginate(ctx, nil, "not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = store.CursorPaginate(ctx, NewCriteria().OrderAsc("unknown_column"), "")
+	assert.Error(t, err)
+
+	// 游标字段数量与排序不一致
+	page, err := store.CursorPaginate(ctx, NewCriteria().OrderAsc("age"), "")
+	assert.NoError(t, err)
+	assert.Empty(t, page.Items)
+	cursor, err := encodeCursor(ctx, cursorNext, []cursorColumn{}, &TestModel{})
+	assert.NoError(t, err)
+	_, err = store.CursorPaginate(ctx, NewCriteria().OrderAsc("age"), cursor)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func cursorNames(items []TestModel) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
@@ -0,0 +1,144 @@
+package storeit
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound 记录不存在
+	ErrNotFound = errors.New("storeit: record not found")
+	// ErrDuplicateKey 违反唯一约束
+	ErrDuplicateKey = errors.New("storeit: duplicate key")
+	// ErrForeignKeyViolation 违反外键约束
+	ErrForeignKeyViolation = errors.New("storeit: foreign key violation")
+	// ErrNoRowsAffected 更新或删除没有影响任何行
+	ErrNoRowsAffected = errors.New("storeit: no rows affected")
+	// ErrVersionConflict 乐观锁版本不匹配，记录已被其他请求修改
+	ErrVersionConflict = errors.New("storeit: version conflict")
+	// ErrMissingTenant 开启多租户的 store 在 ctx 中找不到租户
+	ErrMissingTenant = errors.New("storeit: missing tenant")
+)
+
+// Error 将底层数据库错误归类为 storeit 的哨兵错误，
+// errors.Is 既可以匹配哨兵错误，也可以匹配原始错误。
+type Error struct {
+	Sentinel error
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Sentinel.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Sentinel
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// 各数据库驱动的错误码
+var (
+	// go-sqlite3 ExtendedCode
+	sqliteDuplicateCodes  = []int64{1555, 2067}
+	sqliteForeignKeyCodes = []int64{787}
+	// go-sql-driver/mysql Number
+	mysqlDuplicateCodes  = []int64{1062, 1586}
+	mysqlForeignKeyCodes = []int64{1216, 1217, 1451, 1452}
+	// pgconn SQLState
+	postgresDuplicateCode  = "23505"
+	postgresForeignKeyCode = "23503"
+)
+
+// TranslateError 将 gorm 及 sqlite/mysql/postgres 驱动的错误转换为 storeit 的哨兵错误，
+// 无法识别的错误原样返回。
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if sentinel := classifyError(err); sentinel != nil {
+		return &Error{Sentinel: sentinel, Err: err}
+	}
+	return err
+}
+
+func classifyError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateKey
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrForeignKeyViolation
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if sentinel := classifyDriverError(e); sentinel != nil {
+			return sentinel
+		}
+	}
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "unique constraint failed"),
+		strings.Contains(message, "duplicate entry"),
+		strings.Contains(message, "duplicate key value"):
+		return ErrDuplicateKey
+	case strings.Contains(message, "foreign key constraint"):
+		return ErrForeignKeyViolation
+	}
+	return nil
+}
+
+// classifyDriverError 通过反射读取驱动错误码，避免引入具体的驱动依赖
+func classifyDriverError(err error) error {
+	if state, ok := err.(interface{ SQLState() string }); ok {
+		switch state.SQLState() {
+		case postgresDuplicateCode:
+			return ErrDuplicateKey
+		case postgresForeignKeyCode:
+			return ErrForeignKeyViolation
+		}
+		return nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(err))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	if code, ok := intField(rv, "ExtendedCode"); ok {
+		return sentinelByCode(code, sqliteDuplicateCodes, sqliteForeignKeyCodes)
+	}
+	if code, ok := intField(rv, "Number"); ok {
+		return sentinelByCode(code, mysqlDuplicateCodes, mysqlForeignKeyCodes)
+	}
+	return nil
+}
+
+func intField(rv reflect.Value, name string) (int64, bool) {
+	field := rv.FieldByName(name)
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint()), true
+	}
+	return 0, false
+}
+
+func sentinelByCode(code int64, duplicateCodes, foreignKeyCodes []int64) error {
+	if slices.Contains(duplicateCodes, code) {
+		return ErrDuplicateKey
+	}
+	if slices.Contains(foreignKeyCodes, code) {
+		return ErrForeignKeyViolation
+	}
+	return nil
+}
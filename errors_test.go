@@ -0,0 +1,76 @@
+package storeit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeSqliteError struct {
+	Code         int
+	ExtendedCode int
+}
+
+func (e fakeSqliteError) Error() string { return "sqlite error" }
+
+type fakeMySQLError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *fakeMySQLError) Error() string { return e.Message }
+
+type fakePgError struct {
+	Code string
+}
+
+func (e *fakePgError) Error() string    { return "pg error" }
+func (e *fakePgError) SQLState() string { return e.Code }
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "gorm 记录不存在", err: gorm.ErrRecordNotFound, expected: ErrNotFound},
+		{name: "gorm 唯一键", err: gorm.ErrDuplicatedKey, expected: ErrDuplicateKey},
+		{name: "gorm 外键", err: gorm.ErrForeignKeyViolated, expected: ErrForeignKeyViolation},
+		{name: "sqlite 唯一键", err: fakeSqliteError{Code: 19, ExtendedCode: 2067}, expected: ErrDuplicateKey},
+		{name: "sqlite 主键", err: fakeSqliteError{Code: 19, ExtendedCode: 1555}, expected: ErrDuplicateKey},
+		{name: "sqlite 外键", err: fakeSqliteError{Code: 19, ExtendedCode: 787}, expected: ErrForeignKeyViolation},
+		{name: "mysql 唯一键", err: &fakeMySQLError{Number: 1062}, expected: ErrDuplicateKey},
+		{name: "mysql 外键", err: &fakeMySQLError{Number: 1452}, expected: ErrForeignKeyViolation},
+		{name: "postgres 唯一键", err: &fakePgError{Code: "23505"}, expected: ErrDuplicateKey},
+		{name: "postgres 外键", err: &fakePgError{Code: "23503"}, expected: ErrForeignKeyViolation},
+		{name: "包装的驱动错误", err: fmt.Errorf("exec: %w", &fakeMySQLError{Number: 1062}), expected: ErrDuplicateKey},
+		{name: "错误信息匹配", err: errors.New("UNIQUE constraint failed: users.email"), expected: ErrDuplicateKey},
+		{name: "错误信息匹配外键", err: errors.New("FOREIGN KEY constraint failed"), expected: ErrForeignKeyViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := TranslateError(tt.err)
+			assert.ErrorIs(t, err, tt.expected)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestTranslateError_Passthrough(t *testing.T) {
+	assert.Nil(t, TranslateError(nil))
+
+	unknown := errors.New("connection refused")
+	assert.Equal(t, unknown, TranslateError(unknown))
+
+	unknownCode := &fakeMySQLError{Number: 1045, Message: "access denied"}
+	assert.Equal(t, unknownCode, TranslateError(unknownCode))
+
+	// 已经转换过的错误不再包装
+	translated := TranslateError(gorm.ErrRecordNotFound)
+	assert.Equal(t, translated, TranslateError(translated))
+	assert.Equal(t, "storeit: record not found: record not found", translated.Error())
+}
@@ -0,0 +1,98 @@
+package storeit
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// HookEvent 钩子触发的时机
+type HookEvent int
+
+const (
+	HookBeforeCreate HookEvent = iota
+	HookAfterCreate
+	HookBeforeUpdate
+	HookAfterUpdate
+	HookBeforeDelete
+	HookAfterDelete
+	HookBeforeFind
+	HookAfterFind
+)
+
+// HookScope 传递给钩子的操作信息，未涉及的字段为零值
+type HookScope[M any] struct {
+	Event HookEvent
+	// Models 创建、保存、删除的模型，或 AfterFind 时查询到的结果
+	Models []*M
+	// Criteria 按条件更新、删除、查询时的条件
+	Criteria *Criteria
+	// ID 按主键更新、删除、查询时的主键
+	ID any
+	// Values 更新时的内容
+	Values any
+}
+
+// Hook 在 store 级别注册的钩子，Before 钩子返回错误时会中止操作，
+// After 钩子返回的错误会作为操作的错误返回。
+type Hook[M any] func(ctx context.Context, scope *HookScope[M]) error
+
+// RegisterHook 返回注册了钩子的新 store，同一时机的钩子按注册顺序执行
+func (r *GormStore[M]) RegisterHook(event HookEvent, hook Hook[M]) *GormStore[M] {
+	nr := r.clone()
+	if nr.hooks == nil {
+		nr.hooks = make(map[HookEvent][]Hook[M])
+	}
+	nr.hooks[event] = append(nr.hooks[event], hook)
+	return nr
+}
+
+func (r *GormStore[M]) runHooks(ctx context.Context, event HookEvent, scope *HookScope[M]) error {
+	for _, hook := range r.hooks[event] {
+		scope.Event = event
+		if err := hook(ctx, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write 执行写操作，在前后执行钩子，成功后使缓存失效
+func (r *GormStore[M]) write(ctx context.Context, before, after HookEvent, scope *HookScope[M], exec func() *gorm.DB) *gorm.DB {
+	if err := r.runHooks(ctx, before, scope); err != nil {
+		return r.errorDB(ctx, err)
+	}
+	tx := exec()
+	if tx.Error != nil {
+		return tx
+	}
+	r.invalidateCache(ctx)
+	if err := r.runHooks(ctx, after, scope); err != nil {
+		_ = tx.AddError(err)
+	}
+	return tx
+}
+
+// isNewRecord 主键为空时认为是新建记录
+func (r *GormStore[M]) isNewRecord(ctx context.Context, model *M) bool {
+	s, err := r.parseSchema()
+	if err != nil || len(s.PrimaryFields) == 0 {
+		return false
+	}
+	rv := reflect.ValueOf(model).Elem()
+	for _, field := range s.PrimaryFields {
+		if _, zero := field.ValueOf(ctx, rv); zero {
+			return true
+		}
+	}
+	return false
+}
+
+func modelPointers[M any](models []M) []*M {
+	pointers := make([]*M, 0, len(models))
+	for i := range models {
+		pointers = append(pointers, &models[i])
+	}
+	return pointers
+}
@@ -0,0 +1,134 @@
+package storeit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormStore_HooksCreate(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	var events []HookEvent
+	store := New[TestModel](db).
+		RegisterHook(HookBeforeCreate, func(ctx context.Context, scope *HookScope[TestModel]) error {
+			events = append(events, scope.Event)
+			// 在创建前修改模型
+			for _, model := range scope.Models {
+				model.Email = "stamped@test.com"
+			}
+			return nil
+		}).
+		RegisterHook(HookAfterCreate, func(ctx context.Context, scope *HookScope[TestModel]) error {
+			events = append(events, scope.Event)
+			for _, model := range scope.Models {
+				assert.NotZero(t, model.ID)
+			}
+			return nil
+		})
+
+	model := &TestModel{Name: "User 1", Age: 20}
+	err := store.Create(ctx, model).Error
+	assert.NoError(t, err)
+	err = store.Creates(ctx, []TestModel{{Name: "User 2"}, {Name: "User 3"}}).Error
+	assert.NoError(t, err)
+	assert.Equal(t, []HookEvent{HookBeforeCreate, HookAfterCreate, HookBeforeCreate, HookAfterCreate}, events)
+
+	var emails []string
+	err = store.Pluck(ctx, "email", &emails, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stamped@test.com", "stamped@test.com", "stamped@test.com"}, emails)
+
+	// Save 主键为空时触发 create 钩子
+	events = nil
+	err = store.Save(ctx, TestModel{Name: "User 4"}).Error
+	assert.NoError(t, err)
+	assert.Equal(t, []HookEvent{HookBeforeCreate, HookAfterCreate}, events)
+}
+
+func TestGormStore_HooksAbort(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	base := New[TestModel](db)
+
+	err := base.Create(ctx, &TestModel{Name: "User 1", Age: 20}).Error
+	assert.NoError(t, err)
+
+	denied := errors.New("denied")
+	deny := func(ctx context.Context, scope *HookScope[TestModel]) error {
+		return denied
+	}
+
+	err = base.RegisterHook(HookBeforeCreate, deny).Create(ctx, &TestModel{Name: "User 2"}).Error
+	assert.ErrorIs(t, err, denied)
+	err = base.RegisterHook(HookBeforeUpdate, deny).UpdateById(ctx, 1, "age", 30).Error
+	assert.ErrorIs(t, err, denied)
+	err = base.RegisterHook(HookBeforeDelete, deny).DeleteById(ctx, 1).Error
+	assert.ErrorIs(t, err, denied)
+	_, err = base.RegisterHook(HookBeforeFind, deny).FindByID(ctx, 1)
+	assert.ErrorIs(t, err, denied)
+
+	// 被中止的操作没有执行
+	found, err := base.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, found.Age)
+	count, err := base.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// 注册钩子不影响原 store
+	assert.Empty(t, base.hooks)
+
+	// After 钩子的错误会返回给调用方
+	_, err = base.RegisterHook(HookAfterFind, deny).Find(ctx, nil)
+	assert.ErrorIs(t, err, denied)
+
+	// 中止时只返回钩子的错误，不会附带租户等条件的错误
+	tenantStore := New[TestModel](db, WithTenant("name"))
+	err = tenantStore.RegisterHook(HookBeforeUpdate, deny).UpdateById(ctx, 1, "age", 30).Error
+	assert.EqualError(t, err, "denied")
+	err = tenantStore.Create(ctx, &TestModel{Name: "User 3"}).Error
+	assert.ErrorIs(t, err, ErrMissingTenant)
+	assert.EqualError(t, err, ErrMissingTenant.Error())
+}
+
+func TestGormStore_HooksScope(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	var scopes []HookScope[TestModel]
+	record := func(ctx context.Context, scope *HookScope[TestModel]) error {
+		scopes = append(scopes, *scope)
+		return nil
+	}
+	store := New[TestModel](db).
+		RegisterHook(HookBeforeUpdate, record).
+		RegisterHook(HookBeforeDelete, record).
+		RegisterHook(HookAfterFind, record)
+
+	err := store.Creates(ctx, []TestModel{{Name: "User 1", Age: 20}, {Name: "User 2", Age: 25}}).Error
+	assert.NoError(t, err)
+
+	criteria := NewCriteria().WhereGt("age", 20)
+	updates := map[string]any{"age": 30}
+	err = store.Updates(ctx, updates, criteria).Error
+	assert.NoError(t, err)
+	err = store.DeleteById(ctx, 1).Error
+	assert.NoError(t, err)
+	found, err := store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	assert.Len(t, scopes, 3)
+	assert.Equal(t, HookBeforeUpdate, scopes[0].Event)
+	assert.Equal(t, criteria, scopes[0].Criteria)
+	assert.Equal(t, updates, scopes[0].Values)
+	assert.Equal(t, HookBeforeDelete, scopes[1].Event)
+	assert.Equal(t, 1, scopes[1].ID)
+	assert.Equal(t, HookAfterFind, scopes[2].Event)
+	assert.Len(t, scopes[2].Models, 1)
+	assert.Equal(t, "User 2", scopes[2].Models[0].Name)
+}
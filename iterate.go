@@ -0,0 +1,62 @@
+package storeit
+
+import (
+	"context"
+)
+
+// Iterate 通过 Rows() 逐行读取查询结果并调用 fn，内存中同时只保留一行，适合导出等大结果集的场景。
+// ctx 取消或 fn 返回错误时停止遍历并返回该错误。逐行读取不支持 Preload。
+// 遍历期间会占用一个数据库连接，连接池较小时不要在 fn 中执行其他查询。
+func (r *GormStore[M]) Iterate(ctx context.Context, criteria *Criteria, fn func(M) error) error {
+	var model M
+	if err := r.runHooks(ctx, HookBeforeFind, &HookScope[M]{Criteria: criteria}); err != nil {
+		return err
+	}
+	db := r.presentFind(ctx, criteria).Model(&model)
+	rows, err := db.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		var item M
+		if err = db.ScanRows(rows, &item); err != nil {
+			return err
+		}
+		if err = r.runHooks(ctx, HookAfterFind, &HookScope[M]{Criteria: criteria, Models: []*M{&item}}); err != nil {
+			return err
+		}
+		if err = fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Stream 在新的 goroutine 中执行 Iterate，通过 channel 逐行返回查询结果，读取完毕或出错后 channel 会被关闭。
+// channel 关闭后调用返回的 wait 函数获取遍历的错误。提前结束读取时需要取消 ctx，否则 goroutine 会一直阻塞。
+func (r *GormStore[M]) Stream(ctx context.Context, criteria *Criteria) (<-chan M, func() error) {
+	items := make(chan M)
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		defer close(items)
+		err = r.Iterate(ctx, criteria, func(item M) error {
+			select {
+			case items <- item:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return items, func() error {
+		<-done
+		return err
+	}
+}
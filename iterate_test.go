@@ -0,0 +1,93 @@
+package storeit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func seedIterateModels(t *testing.T, store *GormStore[TestModel], n int) {
+	models := make([]TestModel, 0, n)
+	for i := 1; i <= n; i++ {
+		models = append(models, TestModel{Name: fmt.Sprintf("User %d", i), Age: i})
+	}
+	assert.NoError(t, store.CreateInBatches(context.Background(), models, 50).Error)
+}
+
+func TestGormStore_Iterate(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+	seedIterateModels(t, store, 100)
+
+	var ages []int
+	err := store.Iterate(ctx, NewCriteria().WhereGt("age", 90).OrderDesc("age"), func(model TestModel) error {
+		ages = append(ages, model.Age)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{100, 99, 98, 97, 96, 95, 94, 93, 92, 91}, ages)
+
+	// fn 返回错误时停止遍历
+	stop := errors.New("stop")
+	var visited int
+	err = store.Iterate(ctx, nil, func(model TestModel) error {
+		visited++
+		if visited == 3 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 3, visited)
+
+	// ctx 取消时停止遍历
+	cancelCtx, cancel := context.WithCancel(ctx)
+	visited = 0
+	err = store.Iterate(cancelCtx, nil, func(model TestModel) error {
+		visited++
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, visited)
+
+	// after find 钩子逐行触发
+	var hooked int
+	hookStore := store.RegisterHook(HookAfterFind, func(ctx context.Context, scope *HookScope[TestModel]) error {
+		hooked += len(scope.Models)
+		return nil
+	})
+	assert.NoError(t, hookStore.Iterate(ctx, NewCriteria().WhereLte("age", 5), func(TestModel) error { return nil }))
+	assert.Equal(t, 5, hooked)
+}
+
+func TestGormStore_Stream(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+	seedIterateModels(t, store, 50)
+
+	items, wait := store.Stream(ctx, NewCriteria().OrderAsc("age"))
+	var count, sum int
+	for item := range items {
+		count++
+		sum += item.Age
+	}
+	assert.NoError(t, wait())
+	assert.Equal(t, 50, count)
+	assert.Equal(t, 1275, sum)
+
+	// 提前结束读取
+	cancelCtx, cancel := context.WithCancel(ctx)
+	items, wait = store.Stream(cancelCtx, nil)
+	first := <-items
+	assert.NotZero(t, first.ID)
+	cancel()
+	for range items {
+	}
+	assert.ErrorIs(t, wait(), context.Canceled)
+}
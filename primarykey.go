@@ -0,0 +1,131 @@
+package storeit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// FindByIDs 按主键批量查询，主键可以是任意类型，例如 []int64、[]string、[]uuid.UUID。
+// 复合主键时每个 id 为包含同名主键字段的结构体，例如 []OrderKey{{TenantID: 1, Code: "a"}}。
+func FindByIDs[M any, K comparable](ctx context.Context, store *GormStore[M], ids []K) ([]M, error) {
+	var models []M
+	if len(ids) < 1 {
+		return nil, fmt.Errorf("id is empty")
+	}
+	condition, err := store.primaryKeysCondition(ids)
+	if err != nil {
+		return nil, err
+	}
+	if err = store.runHooks(ctx, HookBeforeFind, &HookScope[M]{ID: ids}); err != nil {
+		return nil, err
+	}
+	if err = store.presentFind(ctx, nil).Where(condition).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	if err = store.runHooks(ctx, HookAfterFind, &HookScope[M]{ID: ids, Models: modelPointers(models)}); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// primaryFields 返回模型的主键字段
+func (r *GormStore[M]) primaryFields() ([]*schema.Field, error) {
+	s, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	if len(s.PrimaryFields) == 0 {
+		return nil, fmt.Errorf("primary key not found in %s", s.Name)
+	}
+	return s.PrimaryFields, nil
+}
+
+// primaryKeyCondition 生成按主键查询的条件。
+// 复合主键时 id 为按主键顺序排列的 slice/array、包含同名主键字段的结构体，或以字段名、列名为 key 的 map[string]any。
+func (r *GormStore[M]) primaryKeyCondition(id any) (clause.Expression, error) {
+	fields, err := r.primaryFields()
+	if err != nil {
+		return nil, err
+	}
+	values, err := primaryKeyValues(fields, id)
+	if err != nil {
+		return nil, err
+	}
+	exprs := make([]clause.Expression, 0, len(fields))
+	for i, field := range fields {
+		exprs = append(exprs, clause.Eq{Column: primaryColumn(field), Value: values[i]})
+	}
+	return clause.And(exprs...), nil
+}
+
+// primaryKeysCondition 生成按多个主键查询的条件，单主键使用 IN，复合主键使用 OR 组合
+func (r *GormStore[M]) primaryKeysCondition(ids any) (clause.Expression, error) {
+	fields, err := r.primaryFields()
+	if err != nil {
+		return nil, err
+	}
+	rv := reflect.ValueOf(ids)
+	if len(fields) == 1 {
+		values := make([]any, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i).Interface())
+		}
+		return clause.IN{Column: primaryColumn(fields[0]), Values: values}, nil
+	}
+	exprs := make([]clause.Expression, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		expr, err := r.primaryKeyCondition(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return clause.Or(exprs...), nil
+}
+
+func primaryColumn(field *schema.Field) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+}
+
+func primaryKeyValues(fields []*schema.Field, id any) ([]any, error) {
+	if len(fields) == 1 {
+		return []any{id}, nil
+	}
+	if m, ok := id.(map[string]any); ok {
+		values := make([]any, 0, len(fields))
+		for _, field := range fields {
+			value, ok := m[field.Name]
+			if !ok {
+				if value, ok = m[field.DBName]; !ok {
+					return nil, fmt.Errorf("composite primary key value of %s is missing", field.DBName)
+				}
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(id))
+	if rv.Kind() == reflect.Struct {
+		values := make([]any, 0, len(fields))
+		for _, field := range fields {
+			value := rv.FieldByName(field.Name)
+			if !value.IsValid() {
+				return nil, fmt.Errorf("composite primary key value of %s is missing", field.DBName)
+			}
+			values = append(values, value.Interface())
+		}
+		return values, nil
+	}
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Len() == len(fields) {
+		values := make([]any, 0, len(fields))
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i).Interface())
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("composite primary key requires %d values, got %T", len(fields), id)
+}
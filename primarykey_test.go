@@ -0,0 +1,120 @@
+package storeit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type StringKeyModel struct {
+	Code string `gorm:"primaryKey;size:36"`
+	Name string `gorm:"size:255"`
+}
+
+type CompositeKeyModel struct {
+	TenantID int    `gorm:"primaryKey;autoIncrement:false"`
+	Code     string `gorm:"primaryKey;size:36"`
+	Name     string `gorm:"size:255"`
+}
+
+type compositeKey struct {
+	TenantID int
+	Code     string
+}
+
+func TestGormStore_StringPrimaryKey(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&StringKeyModel{}))
+	store := New[StringKeyModel](db)
+	ctx := context.Background()
+
+	models := []StringKeyModel{
+		{Code: "0b6e1a1c-6d3f-4b1e-9f8a-2c3d4e5f6a7b", Name: "a"},
+		{Code: "1c7f2b2d-7e4a-4c2f-8a9b-3d4e5f6a7b8c", Name: "b"},
+		{Code: "2d8a3c3e-8f5b-4d3a-9b0c-4e5f6a7b8c9d", Name: "c"},
+	}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+
+	found, err := store.FindByID(ctx, models[1].Code)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", found.Name)
+
+	list, err := FindByIDs(ctx, store, []string{models[0].Code, models[2].Code})
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	// 主键列不叫 id
+	tx := store.UpdateById(ctx, models[0].Code, "name", "aa")
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+	tx = store.UpdatesById(ctx, models[1].Code, map[string]any{"name": "bb"})
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+	found, err = store.FindByID(ctx, models[1].Code)
+	assert.NoError(t, err)
+	assert.Equal(t, "bb", found.Name)
+
+	tx = store.DeleteById(ctx, models[2].Code)
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = FindByIDs(ctx, store, []string{})
+	assert.Error(t, err)
+}
+
+func TestGormStore_CompositePrimaryKey(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&CompositeKeyModel{}))
+	store := New[CompositeKeyModel](db)
+	ctx := context.Background()
+
+	models := []CompositeKeyModel{
+		{TenantID: 1, Code: "a", Name: "1a"},
+		{TenantID: 1, Code: "b", Name: "1b"},
+		{TenantID: 2, Code: "a", Name: "2a"},
+	}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+
+	// slice、结构体和 map 三种形式
+	found, err := store.FindByID(ctx, []any{2, "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2a", found.Name)
+	found, err = store.FindByID(ctx, compositeKey{TenantID: 1, Code: "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1a", found.Name)
+	found, err = store.FindByID(ctx, map[string]any{"TenantID": 1, "code": "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1b", found.Name)
+
+	list, err := FindByIDs(ctx, store, []compositeKey{{TenantID: 1, Code: "a"}, {TenantID: 2, Code: "a"}})
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	tx := store.UpdatesById(ctx, []any{1, "a"}, map[string]any{"name": "x"})
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+
+	tx = store.DeleteById(ctx, []any{1, "b"})
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+
+	// 复合主键值的数量不匹配
+	_, err = store.FindByID(ctx, 1)
+	assert.Error(t, err)
+	_, err = store.FindByID(ctx, map[string]any{"code": "a"})
+	assert.Error(t, err)
+	assert.Error(t, store.DeleteById(ctx, []any{1}).Error)
+
+	// 游标分页使用复合主键的每一列作为 tiebreaker
+	page, err := store.CursorPaginate(ctx, NewCriteria().Limit(1), "")
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	page, err = store.CursorPaginate(ctx, NewCriteria().Limit(1), page.NextCursor)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, 2, page.Items[0].TenantID)
+}
@@ -0,0 +1,105 @@
+package storeit
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+	"golang.org/x/exp/slices"
+)
+
+// CriteriaAllowlist 允许查询的字段和每个字段允许的 operator，operator 为空表示允许所有 operator。
+// 字段同时也是允许排序的字段。
+type CriteriaAllowlist map[string][]string
+
+var filterKeyRegexp = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// ParseCriteriaQuery 解析查询参数生成 Criteria，支持的格式：
+//
+//	filter[status][eq]=active&filter[weight][gte]=10&filter[id][in]=1,2,3&sort=-created_at,name&page=2&per_page=20
+//
+// filter[field]=value 等同于 filter[field][eq]=value，字段和 operator 不在 allowlist 中时返回错误，其他参数会被忽略。
+// isnull、notnull 的值按 bool 解析，例如 filter[email][isnull]=false 等同于 filter[email][notnull]=true。
+func ParseCriteriaQuery(values url.Values, allowlist CriteriaAllowlist) (*Criteria, error) {
+	criteria := NewCriteria()
+	// 按 key 排序，保证生成的条件顺序稳定
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values.Get(key)
+		switch key {
+		case criteriaSort:
+			for _, order := range strings.Split(value, ",") {
+				order = strings.TrimSpace(order)
+				field := strings.TrimLeft(order, "+-")
+				if field == "" {
+					continue
+				}
+				if _, ok := allowlist[field]; !ok {
+					return nil, fmt.Errorf("criteria sort field %s is not allowed", field)
+				}
+				criteria.Order(field, strings.HasPrefix(order, "-"))
+			}
+			continue
+		case criteriaPage:
+			page, err := cast.ToIntE(value)
+			if err != nil {
+				return nil, fmt.Errorf("criteria page %q is invalid: %w", value, err)
+			}
+			criteria.Page(page)
+			continue
+		case criteriaPerPage:
+			perPage, err := cast.ToIntE(value)
+			if err != nil {
+				return nil, fmt.Errorf("criteria per_page %q is invalid: %w", value, err)
+			}
+			criteria.PerPage(perPage)
+			continue
+		}
+
+		matches := filterKeyRegexp.FindStringSubmatch(key)
+		if matches == nil {
+			continue
+		}
+		field, operator := matches[1], matches[2]
+		if operator == "" {
+			operator = "eq"
+		}
+		operators, ok := allowlist[field]
+		if !ok {
+			return nil, fmt.Errorf("criteria filter field %s is not allowed", field)
+		}
+		if len(operators) > 0 && !slices.Contains(operators, operator) {
+			return nil, fmt.Errorf("criteria operator %s is not allowed on field %s", operator, field)
+		}
+		if slices.Contains(controlOperator, operator) {
+			return nil, fmt.Errorf("unknown criteria operator: %s", operator)
+		}
+
+		var fieldValue any = value
+		if _, ok = sliceConditionMapping[operator]; ok || operator == criteriaBetween {
+			// 重复的参数作为多个值，单个参数按英文逗号分隔
+			if len(values[key]) > 1 {
+				fieldValue = values[key]
+			}
+			items, err := toSliceValues(fieldValue)
+			if err != nil {
+				return nil, err
+			}
+			if len(items) == 0 && operator != criteriaBetween {
+				continue
+			}
+		}
+		if err := criteria.addCondition(Condition{Field: field, Operator: operator, Value: fieldValue}); err != nil {
+			return nil, err
+		}
+	}
+	return criteria, nil
+}
@@ -0,0 +1,123 @@
+package storeit
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCriteriaQuery(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	for _, model := range []TestModel{
+		{Name: "Alice", Age: 20, Email: "alice@example.com"},
+		{Name: "Bob", Age: 30, Email: "bob@example.com"},
+		{Name: "Carol", Age: 40},
+		{Name: "Dave", Age: 50, Email: "dave@test.com"},
+	} {
+		model := model
+		assert.NoError(t, store.Create(ctx, &model).Error)
+	}
+
+	allowlist := CriteriaAllowlist{
+		"name":  nil,
+		"age":   {"eq", "gte", "lte", "in", "between"},
+		"email": {"like", "notnull"},
+	}
+	values, err := url.ParseQuery("filter[age][gte]=20&filter[email][notnull]=1&filter[email][like]=example&sort=-age,name&page=1&per_page=10&other=1")
+	assert.NoError(t, err)
+	criteria, err := ParseCriteriaQuery(values, allowlist)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"age DESC", "name"}, criteria.orders)
+	assert.Equal(t, 1, criteria.GetPage())
+	assert.Equal(t, 10, criteria.GetPerPage())
+
+	found, err := store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, "Bob", found[0].Name)
+
+	// filter[field]=value 等同于 eq
+	criteria, err = ParseCriteriaQuery(url.Values{"filter[name]": {"Carol"}}, allowlist)
+	assert.NoError(t, err)
+	found, err = store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, 40, found[0].Age)
+
+	// in 支持英文逗号分隔和重复参数
+	criteria, err = ParseCriteriaQuery(url.Values{"filter[age][in]": {"20,30"}}, allowlist)
+	assert.NoError(t, err)
+	count, err := store.Count(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	criteria, err = ParseCriteriaQuery(url.Values{"filter[age][in]": {"20", "30", "40"}}, allowlist)
+	assert.NoError(t, err)
+	count, err = store.Count(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// between
+	criteria, err = ParseCriteriaQuery(url.Values{"filter[age][between]": {"30,50"}}, allowlist)
+	assert.NoError(t, err)
+	count, err = store.Count(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// isnull、notnull 的值为 false 时使用相反的条件
+	nullAllowlist := CriteriaAllowlist{"email": {"isnull", "notnull"}, "name": {"notnull"}}
+	for query, expected := range map[string]string{
+		"filter[email][isnull]=true":  "email IS NULL",
+		"filter[email][isnull]=false": "email IS NOT NULL",
+		"filter[email][notnull]=1":    "email IS NOT NULL",
+		"filter[name][notnull]=0":     "name IS NULL",
+	} {
+		values, err := url.ParseQuery(query)
+		assert.NoError(t, err)
+		nullCriteria, err := ParseCriteriaQuery(values, nullAllowlist)
+		assert.NoError(t, err)
+		sql, _, err := nullCriteria.ToSQL(db, TestModel{})
+		assert.NoError(t, err)
+		assert.Contains(t, sql, "WHERE "+expected, query)
+	}
+
+	// 生成的 Criteria 可以序列化
+	_, err = criteria.MarshalJSON()
+	assert.NoError(t, err)
+}
+
+func TestParseCriteriaQuery_Errors(t *testing.T) {
+	allowlist := CriteriaAllowlist{
+		"name": {"eq"},
+		"age":  nil,
+	}
+	// 字段不在 allowlist 中
+	_, err := ParseCriteriaQuery(url.Values{"filter[password][eq]": {"x"}}, allowlist)
+	assert.ErrorContains(t, err, "password")
+	// operator 不允许
+	_, err = ParseCriteriaQuery(url.Values{"filter[name][like]": {"x"}}, allowlist)
+	assert.ErrorContains(t, err, "like")
+	// 未知 operator
+	_, err = ParseCriteriaQuery(url.Values{"filter[age][foo]": {"1"}}, allowlist)
+	assert.Error(t, err)
+	_, err = ParseCriteriaQuery(url.Values{"filter[age][sort]": {"1"}}, allowlist)
+	assert.Error(t, err)
+	// 排序字段不在 allowlist 中
+	_, err = ParseCriteriaQuery(url.Values{"sort": {"-created_at"}}, allowlist)
+	assert.ErrorContains(t, err, "created_at")
+	// 分页参数不合法
+	_, err = ParseCriteriaQuery(url.Values{"page": {"abc"}}, allowlist)
+	assert.Error(t, err)
+	_, err = ParseCriteriaQuery(url.Values{"per_page": {"abc"}}, allowlist)
+	assert.Error(t, err)
+	// isnull 的值必须是 bool
+	_, err = ParseCriteriaQuery(url.Values{"filter[age][isnull]": {"maybe"}}, allowlist)
+	assert.Error(t, err)
+	// between 的值必须是两个
+	_, err = ParseCriteriaQuery(url.Values{"filter[age][between]": {"1"}}, allowlist)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package storeit
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// schemaSettingKey 查询时通过 gorm 的 Settings 传递 store 模型的 schema，用于解析关联关系
+const schemaSettingKey = "storeit:schema"
+
+// WhereHas 筛选关联记录满足 fn 中条件的记录，根据模型的关联关系生成 EXISTS 子查询。
+// 支持 has one、has many、belongs to、many2many，relation 为关联字段名，可以用 . 连接嵌套的关联，例如 Orders.Items。
+// fn 为 nil 时只要求存在关联记录，fn 中的条件作用在最内层的关联表上，many2many 时列名需要带上表名避免歧义。
+func (c *Criteria) WhereHas(relation string, fn func(*Criteria)) *Criteria {
+	return c.whereRelation("EXISTS (?)", relation, fn)
+}
+
+// WhereDoesntHave 筛选没有满足 fn 中条件的关联记录的记录，用法同 WhereHas
+func (c *Criteria) WhereDoesntHave(relation string, fn func(*Criteria)) *Criteria {
+	return c.whereRelation("NOT EXISTS (?)", relation, fn)
+}
+
+func (c *Criteria) whereRelation(query, relation string, fn func(*Criteria)) *Criteria {
+	sub := NewCriteria()
+	if fn != nil {
+		fn(sub)
+	}
+	c.scopeClosures = append(c.scopeClosures, func(tx *gorm.DB) *gorm.DB {
+		if tx.Error != nil {
+			return tx
+		}
+		value, ok := tx.Get(schemaSettingKey)
+		if !ok {
+			_ = tx.AddError(fmt.Errorf("criteria relation %s requires model schema", relation))
+			return tx
+		}
+		s, err := value.(func() (*schema.Schema, error))()
+		if err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+		subquery, err := relationSubquery(tx, s, s.Table, strings.Split(relation, "."), sub)
+		if err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+		return tx.Where(query, subquery)
+	})
+	c.opaque = true
+	return c
+}
+
+// relationSubquery 生成 parent 表中记录的关联记录的子查询，criteria 作用在 path 最后一级的关联表上
+func relationSubquery(tx *gorm.DB, s *schema.Schema, parentTable string, path []string, criteria *Criteria) (*gorm.DB, error) {
+	rel, ok := s.Relationships.Relations[path[0]]
+	if !ok {
+		return nil, fmt.Errorf("relation %s not found in %s", path[0], s.Name)
+	}
+	related := rel.FieldSchema
+	table := related.Table
+	sub := tx.Session(&gorm.Session{NewDB: true}).Model(reflect.New(related.ModelType).Interface())
+	if scopes, ok := tx.Get(scopesSettingKey); ok {
+		sub = sub.Set(scopesSettingKey, scopes)
+	}
+	if table == parentTable {
+		// 自关联时为子查询的表设置别名
+		table = related.Table + "_" + strings.ToLower(rel.Name)
+		sub = sub.Table(tx.Statement.Quote(related.Table) + " AS " + table)
+	}
+
+	var joins, conds []clause.Expression
+	for _, ref := range rel.References {
+		switch {
+		case ref.PrimaryValue != "":
+			// 多态关联的类型字段
+			valueTable := table
+			if rel.JoinTable != nil {
+				valueTable = rel.JoinTable.Table
+			}
+			conds = append(conds, clause.Eq{Column: clause.Column{Table: valueTable, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+		case rel.JoinTable != nil:
+			foreignKey := clause.Column{Table: rel.JoinTable.Table, Name: ref.ForeignKey.DBName}
+			if ref.OwnPrimaryKey {
+				conds = append(conds, clause.Eq{Column: foreignKey, Value: clause.Column{Table: parentTable, Name: ref.PrimaryKey.DBName}})
+			} else {
+				joins = append(joins, clause.Eq{Column: foreignKey, Value: clause.Column{Table: table, Name: ref.PrimaryKey.DBName}})
+			}
+		case ref.OwnPrimaryKey:
+			// has one、has many 的外键在关联表上
+			conds = append(conds, clause.Eq{Column: clause.Column{Table: table, Name: ref.ForeignKey.DBName}, Value: clause.Column{Table: parentTable, Name: ref.PrimaryKey.DBName}})
+		default:
+			// belongs to 的外键在当前表上
+			conds = append(conds, clause.Eq{Column: clause.Column{Table: table, Name: ref.PrimaryKey.DBName}, Value: clause.Column{Table: parentTable, Name: ref.ForeignKey.DBName}})
+		}
+	}
+	if len(joins) > 0 {
+		sub = sub.Joins("JOIN ? ON ?", clause.Table{Name: rel.JoinTable.Table}, clause.And(joins...))
+	}
+	sub = sub.Where(clause.And(conds...))
+
+	if len(path) > 1 {
+		nested, err := relationSubquery(tx, related, table, path[1:], criteria)
+		if err != nil {
+			return nil, err
+		}
+		return sub.Where("EXISTS (?)", nested), nil
+	}
+	for _, closure := range criteria.scopeClosures {
+		sub = closure(sub)
+	}
+	// 嵌入外层查询的子查询的错误不会传递到外层查询，需要在这里返回
+	if sub.Error != nil {
+		return nil, sub.Error
+	}
+	return sub, nil
+}
@@ -0,0 +1,156 @@
+package storeit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type RelUser struct {
+	ID        uint `gorm:"primarykey"`
+	Name      string
+	ManagerID *uint
+	Manager   *RelUser
+	Orders    []RelOrder  `gorm:"foreignKey:UserID"`
+	Profile   *RelProfile `gorm:"foreignKey:UserID"`
+	Roles     []RelRole   `gorm:"many2many:rel_user_roles"`
+	DeletedAt gorm.DeletedAt
+
+	// WithCount 等方法的聚合列
+	OrdersCount     int64 `gorm:"->;-:migration"`
+	OrdersSumAmount int64 `gorm:"->;-:migration"`
+	OrdersMaxAmount int64 `gorm:"->;-:migration"`
+	RolesCount      int64 `gorm:"->;-:migration"`
+}
+
+type RelOrder struct {
+	ID        uint `gorm:"primarykey"`
+	UserID    uint
+	User      *RelUser
+	Amount    int
+	DeletedAt gorm.DeletedAt
+}
+
+type RelProfile struct {
+	ID     uint `gorm:"primarykey"`
+	UserID uint
+	Bio    string
+}
+
+type RelRole struct {
+	ID   uint `gorm:"primarykey"`
+	Name string
+}
+
+// seedRelationModels 创建用户和关联记录：
+// Alice 有订单 50、150，Bob 有订单 80 和已删除的订单 200，Carol 没有订单，Bob 和 Carol 的上级是 Alice
+func seedRelationModels(t *testing.T) *gorm.DB {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&RelUser{}, &RelOrder{}, &RelProfile{}, &RelRole{}))
+	admin, editor := RelRole{Name: "admin"}, RelRole{Name: "editor"}
+	alice := RelUser{Name: "Alice", Roles: []RelRole{admin, editor}, Profile: &RelProfile{Bio: "hi"}}
+	assert.NoError(t, db.Create(&alice).Error)
+	bob := RelUser{Name: "Bob", ManagerID: &alice.ID, Roles: []RelRole{alice.Roles[1]}}
+	carol := RelUser{Name: "Carol", ManagerID: &alice.ID}
+	assert.NoError(t, db.Create(&bob).Error)
+	assert.NoError(t, db.Create(&carol).Error)
+	orders := []RelOrder{
+		{UserID: alice.ID, Amount: 50},
+		{UserID: alice.ID, Amount: 150},
+		{UserID: bob.ID, Amount: 80},
+		{UserID: bob.ID, Amount: 200},
+	}
+	assert.NoError(t, db.Create(&orders).Error)
+	assert.NoError(t, db.Delete(&orders[3]).Error)
+	return db
+}
+
+func relUserNames(users []RelUser) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Name)
+	}
+	return names
+}
+
+func TestCriteria_WhereHas(t *testing.T) {
+	db := seedRelationModels(t)
+	store := New[RelUser](db)
+	ctx := context.Background()
+
+	find := func(c *Criteria) []string {
+		users, err := store.Find(ctx, c.OrderAsc("id"))
+		assert.NoError(t, err)
+		return relUserNames(users)
+	}
+
+	// has many，已删除的订单不计入
+	assert.Equal(t, []string{"Alice", "Bob"}, find(NewCriteria().WhereHas("Orders", nil)))
+	assert.Equal(t, []string{"Alice"}, find(NewCriteria().WhereHas("Orders", func(c *Criteria) {
+		c.WhereGt("amount", 100)
+	})))
+	assert.Equal(t, []string{"Carol"}, find(NewCriteria().WhereDoesntHave("Orders", nil)))
+	// has one
+	assert.Equal(t, []string{"Alice"}, find(NewCriteria().WhereHas("Profile", nil)))
+	// many2many
+	assert.Equal(t, []string{"Alice", "Bob"}, find(NewCriteria().WhereHas("Roles", func(c *Criteria) {
+		c.Where("rel_roles.name = ?", "editor")
+	})))
+	assert.Equal(t, []string{"Bob", "Carol"}, find(NewCriteria().WhereDoesntHave("Roles", func(c *Criteria) {
+		c.Where("rel_roles.name = ?", "admin")
+	})))
+	// 自关联的 belongs to 和嵌套关联
+	assert.Equal(t, []string{"Bob", "Carol"}, find(NewCriteria().WhereHas("Manager", func(c *Criteria) {
+		c.Where("name = ?", "Alice")
+	})))
+	assert.Equal(t, []string{"Bob", "Carol"}, find(NewCriteria().WhereHas("Manager.Orders", func(c *Criteria) {
+		c.WhereGt("amount", 100)
+	})))
+
+	// belongs to
+	orders, err := New[RelOrder](db).Find(ctx, NewCriteria().WhereHas("User", func(c *Criteria) {
+		c.Where("name = ?", "Bob")
+	}))
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+	assert.Equal(t, 80, orders[0].Amount)
+
+	// 和其他条件组合
+	count, err := store.Count(ctx, NewCriteria().WhereHas("Orders", nil).Where("name <> ?", "Alice"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// 未知的关联
+	_, err = store.Find(ctx, NewCriteria().WhereHas("Unknown", nil))
+	assert.ErrorContains(t, err, "Unknown")
+	_, err = store.Find(ctx, NewCriteria().WhereHas("Orders.Unknown", nil))
+	assert.Error(t, err)
+}
+
+func TestCriteria_WhereHasScopeAndError(t *testing.T) {
+	db := seedRelationModels(t)
+	store := New[RelUser](db).RegisterScope("big", func(c *Criteria) {
+		c.WhereGt("amount", 100)
+	})
+	ctx := context.Background()
+
+	// 关联条件中可以使用 store 注册的 scope
+	users, err := store.Find(ctx, NewCriteria().WhereHas("Orders", func(c *Criteria) {
+		c.Scope("big")
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Alice"}, relUserNames(users))
+
+	// 关联条件的错误返回给外层查询
+	_, err = store.Find(ctx, NewCriteria().WhereHas("Orders", func(c *Criteria) {
+		c.Scope("nope")
+	}))
+	assert.ErrorContains(t, err, "criteria scope nope not registered")
+
+	// ToSQL 使用 model 解析关联关系
+	sql, _, err := NewCriteria().WhereHas("Orders", nil).ToSQL(db, RelUser{})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "EXISTS (SELECT * FROM `rel_orders`")
+}
@@ -0,0 +1,89 @@
+package storeit
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// Replica 带权重的从库
+type Replica struct {
+	DB     *gorm.DB
+	Weight int
+}
+
+// replicaSet 按权重轮询选择从库，可以在多个 goroutine 间共享
+type replicaSet struct {
+	replicas []*gorm.DB
+	// cumulative[i] 是前 i+1 个从库的权重之和
+	cumulative []uint64
+	counter    uint64
+}
+
+// WithReplicas 配置从库，读操作在从库间轮询
+func WithReplicas(replicas ...*gorm.DB) Option {
+	weighted := make([]Replica, 0, len(replicas))
+	for _, replica := range replicas {
+		weighted = append(weighted, Replica{DB: replica, Weight: 1})
+	}
+	return WithWeightedReplicas(weighted...)
+}
+
+// WithWeightedReplicas 配置带权重的从库，读操作按权重分配，权重小于 1 的从库不参与读
+func WithWeightedReplicas(replicas ...Replica) Option {
+	set := &replicaSet{}
+	var total uint64
+	for _, replica := range replicas {
+		if replica.DB == nil || replica.Weight < 1 {
+			continue
+		}
+		total += uint64(replica.Weight)
+		set.replicas = append(set.replicas, replica.DB)
+		set.cumulative = append(set.cumulative, total)
+	}
+	return func(o *options) {
+		if len(set.replicas) == 0 {
+			o.replicas = nil
+			return
+		}
+		o.replicas = set
+	}
+}
+
+func (s *replicaSet) next() *gorm.DB {
+	total := s.cumulative[len(s.cumulative)-1]
+	n := (atomic.AddUint64(&s.counter, 1) - 1) % total
+	for i, weight := range s.cumulative {
+		if n < weight {
+			return s.replicas[i]
+		}
+	}
+	return s.replicas[len(s.replicas)-1]
+}
+
+// ForcePrimary 返回读操作也使用主库的 store，用于写后立即读的场景
+func (r *GormStore[M]) ForcePrimary() *GormStore[M] {
+	nr := r.clone()
+	nr.forcePrimary = true
+	return nr
+}
+
+// primary 返回写操作使用的连接，优先使用 SetTx 和 ctx 中的事务
+func (r *GormStore[M]) primary(ctx context.Context) *gorm.DB {
+	if r.tx != nil {
+		return r.tx.WithContext(ctx)
+	}
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+// replica 返回读操作使用的连接
+func (r *GormStore[M]) replica(ctx context.Context) *gorm.DB {
+	if r.options.replicas == nil || r.forcePrimary || r.tx != nil || TxFromContext(ctx) != nil {
+		return r.primary(ctx)
+	}
+	return r.options.replicas.next().WithContext(ctx)
+}
@@ -0,0 +1,111 @@
+package storeit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupReplicaDB(t *testing.T, name string, rows ...TestModel) *gorm.DB {
+	dbName := fmt.Sprintf("file:%s_%s?mode=memory&cache=shared", t.Name(), name)
+	db, err := gorm.Open(sqlite.Open(dbName), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	err = db.AutoMigrate(&TestModel{})
+	assert.NoError(t, err)
+	if len(rows) > 0 {
+		err = db.Create(&rows).Error
+		assert.NoError(t, err)
+	}
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestGormStore_Replicas(t *testing.T) {
+	db := setupTestDB(t)
+	replica1 := setupReplicaDB(t, "r1", TestModel{Name: "replica 1", Age: 1})
+	replica2 := setupReplicaDB(t, "r2", TestModel{Name: "replica 2", Age: 2}, TestModel{Name: "replica 2", Age: 2})
+	store := New[TestModel](db, WithReplicas(replica1, replica2))
+	ctx := context.Background()
+
+	// 写操作使用主库
+	err := store.Create(ctx, &TestModel{Name: "primary", Age: 30}).Error
+	assert.NoError(t, err)
+
+	// 读操作在从库间轮询
+	var names []string
+	for i := 0; i < 4; i++ {
+		found, err := store.First(ctx, nil)
+		assert.NoError(t, err)
+		names = append(names, found.Name)
+	}
+	assert.Equal(t, []string{"replica 1", "replica 2", "replica 1", "replica 2"}, names)
+
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	sum, err := store.Sum(ctx, "age", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4), sum)
+
+	// ForcePrimary 读主库
+	found, err := store.ForcePrimary().First(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", found.Name)
+
+	// 事务中读主库
+	tx := db.Begin()
+	found, err = store.SetTx(tx).First(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", found.Name)
+	tx.Rollback()
+
+	err = Transaction(ctx, db, func(ctx context.Context) error {
+		found, err := store.First(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", found.Name)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestGormStore_WeightedReplicas(t *testing.T) {
+	db := setupTestDB(t)
+	replica1 := setupReplicaDB(t, "r1", TestModel{Name: "replica 1"})
+	replica2 := setupReplicaDB(t, "r2", TestModel{Name: "replica 2"})
+	store := New[TestModel](db, WithWeightedReplicas(
+		Replica{DB: replica1, Weight: 3},
+		Replica{DB: replica2, Weight: 1},
+		Replica{DB: db, Weight: 0},
+	))
+	ctx := context.Background()
+
+	hits := map[string]int{}
+	for i := 0; i < 8; i++ {
+		var names []string
+		err := store.Pluck(ctx, "name", &names, nil)
+		assert.NoError(t, err)
+		assert.Len(t, names, 1)
+		hits[names[0]]++
+	}
+	assert.Equal(t, map[string]int{"replica 1": 6, "replica 2": 2}, hits)
+
+	// 没有可用从库时读主库
+	err := db.Create(&TestModel{Name: "primary"}).Error
+	assert.NoError(t, err)
+	found, err := New[TestModel](db, WithWeightedReplicas(Replica{DB: replica1})).First(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", found.Name)
+}
@@ -0,0 +1,57 @@
+package storeit
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// scopesSettingKey 查询时通过 gorm 的 Settings 传递 store 注册的命名 scope
+const scopesSettingKey = "storeit:scopes"
+
+// RegisterScope 返回注册了命名 scope 的新 store，通过 Criteria.Scope(name) 引用。
+// fn 只有添加的条件会生效，排序和分页等会被忽略，重复注册同名 scope 时覆盖之前的注册。
+func (r *GormStore[M]) RegisterScope(name string, fn func(*Criteria)) *GormStore[M] {
+	nr := r.clone()
+	scopes := make(map[string]func(*Criteria), len(r.scopes)+1)
+	for key, scope := range r.scopes {
+		scopes[key] = scope
+	}
+	scopes[name] = fn
+	nr.scopes = scopes
+	return nr
+}
+
+// Scope 引用 store 通过 RegisterScope 注册的命名 scope，查询时展开为 scope 中的条件。
+// scope 按名称序列化，store 没有注册该 scope 时查询返回错误。
+func (c *Criteria) Scope(name string) *Criteria {
+	return c.condition(Condition{Clause: conditionScope, Query: name})
+}
+
+// applyScope 查找 store 注册的命名 scope 并将其条件作用在 tx 上
+func applyScope(tx *gorm.DB, name string) *gorm.DB {
+	var fn func(*Criteria)
+	if value, ok := tx.Get(scopesSettingKey); ok {
+		fn = value.(map[string]func(*Criteria))[name]
+	}
+	if fn == nil {
+		_ = tx.AddError(fmt.Errorf("criteria scope %s not registered", name))
+		return tx
+	}
+	sub := NewCriteria()
+	fn(sub)
+	for _, closure := range sub.scopeClosures {
+		tx = closure(tx)
+	}
+	return tx
+}
+
+// usesScope 判断条件是否引用了命名 scope，scope 的内容由 store 决定，不能用于缓存 key
+func (c *Criteria) usesScope() bool {
+	for _, cond := range c.conditions {
+		if cond.usesScope() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package storeit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGormStore_RegisterScope(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	base := New[TestModel](db)
+	store := base.
+		RegisterScope("adult", func(c *Criteria) {
+			c.WhereGte("age", 18)
+		}).
+		RegisterScope("passed", func(c *Criteria) {
+			// scope 可以引用其他 scope，排序会被忽略
+			c.Scope("adult").WhereGte("score", 60).OrderDesc("score")
+		})
+	models := []TestModel{
+		{Name: "Alice", Age: 16, Score: 90},
+		{Name: "Bob", Age: 20, Score: 50},
+		{Name: "Carol", Age: 30, Score: 70},
+		{Name: "Dave", Age: 40, Score: 80},
+	}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+
+	found, err := store.Find(ctx, NewCriteria().Scope("adult").OrderAsc("age"))
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+	assert.Equal(t, "Bob", found[0].Name)
+
+	criteria := NewCriteria().Scope("passed").WhereLt("age", 35).OrderAsc("age")
+	found, err = store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "Carol", found[0].Name)
+	count, err := store.Count(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// scope 按名称序列化
+	data, err := json.Marshal(criteria)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `{"clause":"scope","query":"passed"}`)
+	parsed, err := ParseCriteriaJSON(data)
+	assert.NoError(t, err)
+	again, err := store.Find(ctx, parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, found, again)
+
+	// 注册 scope 不影响原来的 store
+	_, err = base.Find(ctx, NewCriteria().Scope("adult"))
+	assert.ErrorContains(t, err, "adult")
+	_, err = store.Find(ctx, NewCriteria().Scope("unknown"))
+	assert.Error(t, err)
+
+	// 覆盖同名 scope
+	overridden := store.RegisterScope("adult", func(c *Criteria) {
+		c.WhereGte("age", 35)
+	})
+	count, err = overridden.Count(ctx, NewCriteria().Scope("adult"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = store.Count(ctx, NewCriteria().Scope("adult"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestCriteria_ScopeInGroup(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	store := New[TestModel](db).
+		RegisterScope("adult", func(c *Criteria) {
+			c.WhereGte("age", 18)
+		}).
+		RegisterScope("passed", func(c *Criteria) {
+			c.Scope("adult").WhereGte("score", 60)
+		})
+	models := []TestModel{
+		{Name: "Alice", Age: 16, Score: 90},
+		{Name: "Bob", Age: 20, Score: 50},
+		{Name: "Carol", Age: 30, Score: 70},
+		{Name: "Dave", Age: 40, Score: 80},
+	}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+	names := func(c *Criteria) []string {
+		found, err := store.Find(ctx, c.OrderAsc("age"))
+		assert.NoError(t, err)
+		result := make([]string, 0, len(found))
+		for _, model := range found {
+			result = append(result, model.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Bob", "Carol"}, names(NewCriteria().AndGroup(func(g *Criteria) {
+		g.Scope("adult").WhereLt("age", 35)
+	})))
+	// name = 'Alice' OR (age >= 18 AND score >= 60 AND age > 35)
+	criteria := NewCriteria().Where("name = ?", "Alice").OrGroup(func(g *Criteria) {
+		g.Scope("passed").AndGroup(func(g *Criteria) {
+			g.WhereGt("age", 35)
+		})
+	})
+	assert.Equal(t, []string{"Alice", "Dave"}, names(criteria))
+	assert.Equal(t, []string{"Alice", "Carol", "Dave"}, names(NewCriteria().OrGroup(func(g *Criteria) {
+		g.WhereLt("age", 18).OrGroup(func(g *Criteria) {
+			g.Scope("passed")
+		})
+	})))
+
+	// 条件组中的 scope 同样按名称序列化
+	data, err := json.Marshal(criteria)
+	assert.NoError(t, err)
+	parsed, err := ParseCriteriaJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Dave"}, names(parsed))
+
+	// 包含闭包的条件组
+	assert.Equal(t, []string{"Carol", "Dave"}, names(NewCriteria().AndGroup(func(g *Criteria) {
+		g.Scope("passed").ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("score < ?", 100)
+		})
+	})))
+
+	// 未注册的 scope 返回错误
+	_, err = store.Find(ctx, NewCriteria().AndGroup(func(g *Criteria) {
+		g.Scope("unknown")
+	}))
+	assert.ErrorContains(t, err, "criteria scope unknown not registered")
+	_, err = store.Find(ctx, NewCriteria().OrGroup(func(g *Criteria) {
+		g.Scope("unknown").ScopeClosure(func(tx *gorm.DB) *gorm.DB { return tx })
+	}))
+	assert.ErrorContains(t, err, "criteria scope unknown not registered")
+}
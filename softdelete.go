@@ -0,0 +1,88 @@
+package storeit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	nullTimeType  = reflect.TypeOf(sql.NullTime{})
+)
+
+// OnlyTrashed 只查询已软删除的记录
+func (r *GormStore[M]) OnlyTrashed() *GormStore[M] {
+	nr := r.clone()
+	nr.onlyTrashed = true
+	return nr
+}
+
+// Restore 恢复符合条件的软删除记录，触发 update 钩子
+func (r *GormStore[M]) Restore(ctx context.Context, criteria *Criteria) *gorm.DB {
+	var model M
+	field, err := r.softDeleteField()
+	if err != nil {
+		return r.errorDB(ctx, err)
+	}
+	scope := &HookScope[M]{Criteria: criteria, Values: map[string]any{field.DBName: nil}}
+	return r.write(ctx, HookBeforeUpdate, HookAfterUpdate, scope, func() *gorm.DB {
+		return r.scopeTrashed(r.present(ctx, criteria), field).Model(&model).Update(field.DBName, nil)
+	})
+}
+
+// RestoreById 按主键恢复软删除的记录，触发 update 钩子
+func (r *GormStore[M]) RestoreById(ctx context.Context, id any) *gorm.DB {
+	var model M
+	field, err := r.softDeleteField()
+	if err != nil {
+		return r.errorDB(ctx, err)
+	}
+	scope := &HookScope[M]{ID: id, Values: map[string]any{field.DBName: nil}}
+	return r.write(ctx, HookBeforeUpdate, HookAfterUpdate, scope, func() *gorm.DB {
+		condition, err := r.primaryKeyCondition(id)
+		if err != nil {
+			return r.errorDB(ctx, err)
+		}
+		return r.scopeTrashed(r.present(ctx, nil), field).Model(&model).Where(condition).Update(field.DBName, nil)
+	})
+}
+
+// ForceDelete 永久删除符合条件的记录，包括已软删除的记录，触发 delete 钩子
+func (r *GormStore[M]) ForceDelete(ctx context.Context, criteria *Criteria) *gorm.DB {
+	var model M
+	scope := &HookScope[M]{Criteria: criteria}
+	return r.write(ctx, HookBeforeDelete, HookAfterDelete, scope, func() *gorm.DB {
+		return r.present(ctx, criteria).Unscoped().Delete(&model)
+	})
+}
+
+// softDeleteField 返回模型的软删除字段，支持 gorm.DeletedAt 类型的字段和名为 deleted_at 的 sql.NullTime 字段
+func (r *GormStore[M]) softDeleteField() (*schema.Field, error) {
+	s, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range s.Fields {
+		if field.DBName != "" && field.FieldType == deletedAtType {
+			return field, nil
+		}
+	}
+	if field := s.LookUpField("deleted_at"); field != nil && field.DBName != "" && field.FieldType == nullTimeType {
+		return field, nil
+	}
+	return nil, fmt.Errorf("soft delete column not found in %s", s.Name)
+}
+
+// scopeTrashed 去掉 gorm 的软删除条件，只保留已软删除的记录，db 中已有的条件包含 OR 时先用括号包裹
+func (r *GormStore[M]) scopeTrashed(db *gorm.DB, field *schema.Field) *gorm.DB {
+	return db.Unscoped().Clauses(groupWhere{}).Where(clause.Expr{
+		SQL:  "? IS NOT NULL",
+		Vars: []any{clause.Column{Table: clause.CurrentTable, Name: field.DBName}},
+	})
+}
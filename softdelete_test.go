@@ -0,0 +1,131 @@
+package storeit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type NullTimeModel struct {
+	ID        int    `gorm:"primarykey"`
+	Name      string `gorm:"size:255"`
+	DeletedAt sql.NullTime
+}
+
+func TestGormStore_OnlyTrashedAndRestore(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	models := []TestModel{{Name: "User 1", Age: 20}, {Name: "User 2", Age: 30}, {Name: "User 3", Age: 40}}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+	assert.NoError(t, store.DeleteById(ctx, models[0].ID).Error)
+	assert.NoError(t, store.DeleteById(ctx, models[1].ID).Error)
+
+	trashed, err := store.OnlyTrashed().Find(ctx, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Len(t, trashed, 2)
+	assert.Equal(t, "User 1", trashed[0].Name)
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// 按主键恢复
+	tx := store.RestoreById(ctx, models[0].ID)
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+	found, err := store.FindByID(ctx, models[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "User 1", found.Name)
+
+	// 未删除的记录不会被恢复
+	_, err = store.Checked().RestoreById(ctx, models[2].ID)
+	assert.ErrorIs(t, err, ErrNoRowsAffected)
+
+	// 按条件恢复
+	tx = store.Restore(ctx, NewCriteria().WhereGte("age", 30))
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+	count, err = store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestGormStore_OnlyTrashedOrWhere(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	models := []TestModel{{Name: "a", Age: 20}, {Name: "b", Age: 30}}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+	assert.NoError(t, store.DeleteById(ctx, models[0].ID).Error)
+
+	// OR 条件不能绕过只查询已删除记录的条件
+	criteria := func() *Criteria {
+		return NewCriteria().Where("name = ?", "x").OrWhere("name = ?", "b")
+	}
+	trashed, err := store.OnlyTrashed().Find(ctx, criteria())
+	assert.NoError(t, err)
+	assert.Empty(t, trashed)
+	trashed, err = store.OnlyTrashed().Find(ctx, NewCriteria().Where("name = ?", "x").OrWhere("name = ?", "a"))
+	assert.NoError(t, err)
+	assert.Len(t, trashed, 1)
+
+	// Restore 只恢复已删除的记录
+	tx := store.Restore(ctx, NewCriteria().Where("name = ?", "b").OrWhere("name = ?", "x"))
+	assert.NoError(t, tx.Error)
+	assert.Zero(t, tx.RowsAffected)
+}
+
+func TestGormStore_ForceDelete(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	models := []TestModel{{Name: "User 1", Age: 20}, {Name: "User 2", Age: 30}}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+	assert.NoError(t, store.DeleteById(ctx, models[0].ID).Error)
+
+	// 已软删除的记录也会被永久删除
+	tx := store.ForceDelete(ctx, NewCriteria().WhereLte("age", 30))
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(2), tx.RowsAffected)
+	count, err := store.Unscoped().Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestGormStore_NullTimeSoftDelete(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&NullTimeModel{}))
+	store := New[NullTimeModel](db)
+	ctx := context.Background()
+
+	models := []NullTimeModel{{Name: "a"}, {Name: "b", DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}}}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+
+	trashed, err := store.OnlyTrashed().Find(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, trashed, 1)
+	assert.Equal(t, "b", trashed[0].Name)
+
+	assert.NoError(t, store.RestoreById(ctx, models[1].ID).Error)
+	trashed, err = store.OnlyTrashed().Find(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, trashed)
+}
+
+func TestGormStore_SoftDeleteColumnMissing(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&UniqueModel{}))
+	store := New[UniqueModel](db)
+	ctx := context.Background()
+
+	assert.Error(t, store.Restore(ctx, nil).Error)
+	assert.Error(t, store.RestoreById(ctx, 1).Error)
+	_, err := store.OnlyTrashed().Find(ctx, nil)
+	assert.Error(t, err)
+}
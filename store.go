@@ -2,12 +2,12 @@ package storeit
 
 import (
 	"context"
-	"fmt"
-	"sync"
+	"strings"
+	"time"
 
-	"github.com/jinzhu/copier"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type gormClosure func(tx *gorm.DB) *gorm.DB
@@ -19,27 +19,52 @@ type Pagination[M any] struct {
 	Items   []M   `json:"items"`
 }
 
+// GormStore 是不可变的：Columns、Hidden、ScopeClosure、SetTx、Unscoped 等构造方法总是返回新的实例，
+// 查询和写入方法不会修改 store 的状态，因此同一个 *GormStore[M] 可以作为包级单例在多个 goroutine 间共享。
 type GormStore[M interface{}] struct {
 	tx            *gorm.DB
 	db            *gorm.DB
 	columns       []string
 	hidden        []string
 	scopeClosures []gormClosure
-	mu            sync.Mutex
 	unscoped      bool
-}
-
-func New[M any](db *gorm.DB) *GormStore[M] {
-	return &GormStore[M]{
+	withoutTenant bool
+	forcePrimary  bool
+	onlyTrashed   bool
+	options       options
+	hooks         map[HookEvent][]Hook[M]
+	scopes        map[string]func(*Criteria)
+	relationAggs  []relationAgg
+}
+
+// Option 配置 GormStore
+type Option func(*options)
+
+type options struct {
+	versionColumn  string
+	tenantColumn   string
+	tenantResolver TenantResolver
+	replicas       *replicaSet
+	cache          Cache
+	cacheTTL       time.Duration
+	pendingTxs     *pendingTxs
+}
+
+func New[M any](db *gorm.DB, opts ...Option) *GormStore[M] {
+	store := &GormStore[M]{
 		db: db,
 	}
+	for _, opt := range opts {
+		opt(&store.options)
+	}
+	return store
 }
 
 func (r *GormStore[M]) SetTx(tx *gorm.DB) *GormStore[M] {
 	if tx == nil {
 		return r
 	}
-	nr := r.onceClone()
+	nr := r.clone()
 	nr.tx = tx
 	return nr
 }
@@ -49,13 +74,13 @@ func (r *GormStore[M]) Insert(ctx context.Context, model *M) *gorm.DB {
 }
 
 func (r *GormStore[M]) Unscoped() *GormStore[M] {
-	nr := r.onceClone()
+	nr := r.clone()
 	nr.unscoped = true
 	return nr
 }
 
 func (r *GormStore[M]) WithTrashed(with bool) *GormStore[M] {
-	nr := r.onceClone()
+	nr := r.clone()
 	nr.unscoped = with
 	return nr
 }
@@ -65,8 +90,7 @@ func (r *GormStore[M]) Hidden(fields []string) *GormStore[M] {
 }
 
 func (r *GormStore[M]) Emit(fields []string) *GormStore[M] {
-	nr := r.onceClone()
-	return nr.Hidden(fields)
+	return r.Hidden(fields)
 }
 
 func (r *GormStore[M]) Columns(fields []string) *GormStore[M] {
@@ -74,75 +98,124 @@ func (r *GormStore[M]) Columns(fields []string) *GormStore[M] {
 }
 
 func (r *GormStore[M]) Create(ctx context.Context, model *M) *gorm.DB {
-	tx := r.present(ctx, nil).Create(model)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{Models: []*M{model}}
+	return r.write(ctx, HookBeforeCreate, HookAfterCreate, scope, func() *gorm.DB {
+		if err := r.stampTenant(ctx, scope.Models); err != nil {
+			return r.errorDB(ctx, err)
+		}
+		return r.present(ctx, nil).Create(model)
+	})
 }
 
 func (r *GormStore[M]) Creates(ctx context.Context, models []M) *gorm.DB {
-	tx := r.present(ctx, nil).Create(&models)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{Models: modelPointers(models)}
+	return r.write(ctx, HookBeforeCreate, HookAfterCreate, scope, func() *gorm.DB {
+		if err := r.stampTenant(ctx, scope.Models); err != nil {
+			return r.errorDB(ctx, err)
+		}
+		return r.present(ctx, nil).Create(&models)
+	})
 }
 
 func (r *GormStore[M]) CreateInBatches(ctx context.Context, models []M, batchSize int) *gorm.DB {
-	tx := r.present(ctx, nil).CreateInBatches(&models, batchSize)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{Models: modelPointers(models)}
+	return r.write(ctx, HookBeforeCreate, HookAfterCreate, scope, func() *gorm.DB {
+		if err := r.stampTenant(ctx, scope.Models); err != nil {
+			return r.errorDB(ctx, err)
+		}
+		return r.present(ctx, nil).CreateInBatches(&models, batchSize)
+	})
 }
 
 func (r *GormStore[M]) Delete(ctx context.Context, model *M) *gorm.DB {
-	tx := r.present(ctx, nil).Delete(model)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{Models: []*M{model}}
+	return r.write(ctx, HookBeforeDelete, HookAfterDelete, scope, func() *gorm.DB {
+		return r.present(ctx, nil).Delete(model)
+	})
 }
 
 func (r *GormStore[M]) Deletes(ctx context.Context, criteria *Criteria) *gorm.DB {
 	var model M
-	tx := r.present(ctx, criteria).Delete(&model)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{Criteria: criteria}
+	return r.write(ctx, HookBeforeDelete, HookAfterDelete, scope, func() *gorm.DB {
+		return r.present(ctx, criteria).Delete(&model)
+	})
 }
 
 func (r *GormStore[M]) DeleteById(ctx context.Context, id any) *gorm.DB {
 	var model M
-	tx := r.present(ctx, nil).Delete(&model, &id)
-	r.reset()
-
-	return tx
+	scope := &HookScope[M]{ID: id}
+	return r.write(ctx, HookBeforeDelete, HookAfterDelete, scope, func() *gorm.DB {
+		condition, err := r.primaryKeyCondition(id)
+		if err != nil {
+			return r.errorDB(ctx, err)
+		}
+		return r.present(ctx, nil).Where(condition).Delete(&model)
+	})
 }
 
 func (r *GormStore[M]) Updates(ctx context.Context, attributes any, criteria *Criteria) *gorm.DB {
 	var model M
-	tx := r.present(ctx, criteria).Model(&model).Updates(attributes)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{Criteria: criteria, Values: attributes}
+	return r.write(ctx, HookBeforeUpdate, HookAfterUpdate, scope, func() *gorm.DB {
+		return r.present(ctx, criteria).Model(&model).Updates(attributes)
+	})
 }
 
+// Save 保存模型，模型有版本字段时使用乐观锁更新，版本不匹配时返回 ErrVersionConflict。
+// 主键为空时触发 create 钩子，否则触发 update 钩子。
 func (r *GormStore[M]) Save(ctx context.Context, model M) *gorm.DB {
-	tx := r.present(ctx, nil).Save(&model)
-	r.reset() // 添加这一行，确保状态被重置
-	return tx
+	before, after := HookBeforeUpdate, HookAfterUpdate
+	isNew := r.isNewRecord(ctx, &model)
+	if isNew {
+		before, after = HookBeforeCreate, HookAfterCreate
+	}
+	scope := &HookScope[M]{Models: []*M{&model}}
+	return r.write(ctx, before, after, scope, func() *gorm.DB {
+		if err := r.stampTenant(ctx, scope.Models); err != nil {
+			return r.errorDB(ctx, err)
+		}
+		field, err := r.versionField()
+		if err != nil {
+			return r.errorDB(ctx, err)
+		}
+		if field == nil && r.options.tenantColumn != "" && !isNew {
+			// gorm 的 Save 在更新不到记录时会退化为 upsert，可能覆盖其他租户的数据
+			return r.present(ctx, nil).Model(&model).Select("*").Updates(&model)
+		}
+		if field == nil {
+			return r.present(ctx, nil).Save(&model)
+		}
+		return r.saveWithVersion(ctx, field, &model)
+	})
 }
 
+// FindByIDs 按 int64 主键批量查询，其他类型的主键使用包级函数 FindByIDs
 func (r *GormStore[M]) FindByIDs(ctx context.Context, ids []int64) ([]M, error) {
-	var models []M
-	if len(ids) < 1 {
-		return nil, fmt.Errorf("id is empty")
-	}
-	err := r.present(ctx, nil).Find(&models, ids).Error
-	r.reset()
-	if err != nil {
-		return nil, err
-	}
-	return models, nil // 修改为返回 nil 而不是 err
+	return FindByIDs(ctx, r, ids)
 }
 
+// FindByID 按主键查询，复合主键时 id 为按主键顺序排列的 slice、包含同名主键字段的结构体，或以字段名为 key 的 map[string]any
 func (r *GormStore[M]) FindByID(ctx context.Context, id any) (*M, error) {
 	var model M
-	err := r.present(ctx, nil).First(&model, id).Error
-	r.reset()
-	if err != nil {
+	if err := r.runHooks(ctx, HookBeforeFind, &HookScope[M]{ID: id}); err != nil {
+		return nil, err
+	}
+	key := r.cacheKey(ctx, "id", id)
+	if cached, ok := r.loadCache(ctx, key).(M); ok {
+		model = cached
+	} else {
+		condition, err := r.primaryKeyCondition(id)
+		if err != nil {
+			return nil, err
+		}
+		err = r.presentFind(ctx, nil).Where(condition).First(&model).Error
+		if err != nil {
+			return nil, err
+		}
+		r.storeCache(ctx, key, model)
+	}
+	if err := r.runHooks(ctx, HookAfterFind, &HookScope[M]{ID: id, Models: []*M{&model}}); err != nil {
 		return nil, err
 	}
 	return &model, nil // 修改为返回 nil 而不是 err
@@ -150,11 +223,16 @@ func (r *GormStore[M]) FindByID(ctx context.Context, id any) (*M, error) {
 
 func (r *GormStore[M]) First(ctx context.Context, criteria *Criteria) (*M, error) {
 	var model M
-	err := r.present(ctx, criteria).Take(&model).Error
-	r.reset()
+	if err := r.runHooks(ctx, HookBeforeFind, &HookScope[M]{Criteria: criteria}); err != nil {
+		return nil, err
+	}
+	err := r.presentFind(ctx, criteria).Take(&model).Error
 	if err != nil {
 		return nil, err
 	}
+	if err = r.runHooks(ctx, HookAfterFind, &HookScope[M]{Criteria: criteria, Models: []*M{&model}}); err != nil {
+		return nil, err
+	}
 	return &model, nil // 修改为返回 nil 而不是 err
 }
 
@@ -163,127 +241,119 @@ func (r *GormStore[M]) Exists(ctx context.Context, criteria *Criteria) (bool, er
 	if err != nil {
 		return false, err
 	}
-	// 移除这里的 r.reset() 调用，因为 Count 方法已经调用了
 	return count > 0, nil
 }
 
 func (r *GormStore[M]) Update(ctx context.Context, column string, value interface{}, criteria *Criteria) *gorm.DB {
 	var model M
-	tx := r.present(ctx, criteria).Model(&model).Update(column, value)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{Criteria: criteria, Values: map[string]any{column: value}}
+	return r.write(ctx, HookBeforeUpdate, HookAfterUpdate, scope, func() *gorm.DB {
+		return r.present(ctx, criteria).Model(&model).Update(column, value)
+	})
 }
 
 func (r *GormStore[M]) UpdateById(ctx context.Context, id any, column string, value interface{}) *gorm.DB {
 	var model M
-	tx := r.present(ctx, nil).Model(&model).Where("id", id).Update(column, value)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{ID: id, Values: map[string]any{column: value}}
+	return r.write(ctx, HookBeforeUpdate, HookAfterUpdate, scope, func() *gorm.DB {
+		condition, err := r.primaryKeyCondition(id)
+		if err != nil {
+			return r.errorDB(ctx, err)
+		}
+		return r.present(ctx, nil).Model(&model).Where(condition).Update(column, value)
+	})
 }
 
+// UpdatesById 按主键更新，模型有版本字段时自动递增版本，
+// updates 中带有版本值时会校验版本，不匹配时返回 ErrVersionConflict
 func (r *GormStore[M]) UpdatesById(ctx context.Context, id any, updates interface{}) *gorm.DB {
 	var model M
-	tx := r.present(ctx, nil).Model(&model).Where("id", id).Updates(updates)
-	r.reset()
-	return tx
+	scope := &HookScope[M]{ID: id, Values: updates}
+	return r.write(ctx, HookBeforeUpdate, HookAfterUpdate, scope, func() *gorm.DB {
+		condition, err := r.primaryKeyCondition(id)
+		if err != nil {
+			return r.errorDB(ctx, err)
+		}
+		field, err := r.versionField()
+		if err != nil {
+			return r.errorDB(ctx, err)
+		}
+		if field == nil {
+			return r.present(ctx, nil).Model(&model).Where(condition).Updates(updates)
+		}
+		return r.updatesWithVersion(ctx, field, r.present(ctx, nil).Model(&model).Where(condition), updates)
+	})
 }
 
 // FindInBatches finds all records in batches of batchSize
 func (r *GormStore[M]) FindInBatches(ctx context.Context, models *[]M, batchSize int, fc func(tx *gorm.DB, batch int) error, criteria *Criteria) error {
-	err := r.present(ctx, criteria).FindInBatches(models, batchSize, fc).Error
-	r.reset()
-	return err
+	return r.presentFind(ctx, criteria).FindInBatches(models, batchSize, fc).Error
 }
 
 // Count Retrieve the "count" result of the query.
 func (r *GormStore[M]) Count(ctx context.Context, criteria *Criteria) (i int64, err error) {
-	var c Criteria
 	var model M
-	if criteria != nil {
-		err = copier.Copy(&c, criteria)
-		if err != nil {
-			return
-		}
-	}
+	c := criteria.Clone()
 	c.unsetOrder()
 	c.unsetLimit()
-	err = r.present(ctx, &c).Model(&model).Count(&i).Error
-	r.reset()
+	err = r.presentRead(ctx, c).Model(&model).Count(&i).Error
 	return
 }
 
 // Sum Retrieve the sum of the values of a given column.
 func (r *GormStore[M]) Sum(ctx context.Context, column string, criteria *Criteria) (sum float64, err error) {
-	var c Criteria
-	var model M
 	var result struct {
 		Total float64
 	}
-	if criteria != nil {
-		err = copier.Copy(&c, criteria)
-		if err != nil {
-			return
-		}
-	}
-	c.unsetOrder()
-	c.unsetLimit()
-	err = r.present(ctx, &c).Model(&model).Select("SUM(" + column + ") as total").Scan(&result).Error
-	r.reset()
-	if err != nil {
-		return
-	}
-	return result.Total, nil
+	err = r.aggregate(ctx, criteria, Agg{Func: AggSum, Column: column, Alias: "total"}, func(db *gorm.DB) error {
+		return db.Scan(&result).Error
+	})
+	return result.Total, err
 }
 
 // Avg Retrieve the average of the values of a given column.
 func (r *GormStore[M]) Avg(ctx context.Context, column string, criteria *Criteria) (avg float64, err error) {
-	var c Criteria
-	var model M
-	if criteria != nil {
-		err = copier.Copy(&c, criteria)
-		if err != nil {
-			return
-		}
-	}
 	var result struct {
 		Avg float64
 	}
-	c.unsetOrder()
-	c.unsetLimit()
-	err = r.present(ctx, &c).Model(&model).Select("AVG(" + column + ") as avg").Scan(&result).Error
-	r.reset()
-	if err != nil {
-		return
-	}
-	return result.Avg, nil
+	err = r.aggregate(ctx, criteria, Agg{Func: AggAvg, Column: column, Alias: "avg"}, func(db *gorm.DB) error {
+		return db.Scan(&result).Error
+	})
+	return result.Avg, err
 }
 
-func (r *GormStore[M]) Scan(ctx context.Context, criteria *Criteria, dst any) (err error) {
+func (r *GormStore[M]) Scan(ctx context.Context, criteria *Criteria, dst any) error {
 	var model M
-	err = r.present(ctx, criteria).Model(&model).Scan(dst).Error
-	r.reset()
-	return err
+	return r.presentFind(ctx, criteria).Model(&model).Scan(dst).Error
 }
 
 func (r *GormStore[M]) Find(ctx context.Context, criteria *Criteria) ([]M, error) {
 	var models []M
-
-	err := r.present(ctx, criteria).Find(&models).Error
-	r.reset()
-
-	if err != nil {
+	if err := r.runHooks(ctx, HookBeforeFind, &HookScope[M]{Criteria: criteria}); err != nil {
+		return nil, err
+	}
+	var key string
+	if fingerprint, ok := criteria.fingerprint(); ok {
+		key = r.cacheKey(ctx, "find", fingerprint)
+	}
+	if cached, ok := r.loadCache(ctx, key).([]M); ok {
+		models = append([]M(nil), cached...)
+	} else {
+		err := r.presentFind(ctx, criteria).Find(&models).Error
+		if err != nil {
+			return nil, err
+		}
+		r.storeCache(ctx, key, append([]M(nil), models...))
+	}
+	if err := r.runHooks(ctx, HookAfterFind, &HookScope[M]{Criteria: criteria, Models: modelPointers(models)}); err != nil {
 		return nil, err
 	}
-
 	return models, nil
 }
 
 func (r *GormStore[M]) Pluck(ctx context.Context, column string, dest any, criteria *Criteria) error {
 	var model M
-	err := r.present(ctx, criteria).Model(&model).Pluck(column, dest).Error
-	r.reset()
-
-	return err
+	return r.presentRead(ctx, criteria).Model(&model).Pluck(column, dest).Error
 }
 
 func (r *GormStore[M]) All(ctx context.Context) ([]M, error) {
@@ -325,14 +395,28 @@ func (r *GormStore[M]) Paginate(ctx context.Context, criteria *Criteria) (*Pagin
 	return &pagination, nil
 }
 
+// ExtractCriteria 同包级的 ExtractCriteria，并校验过滤字段和排序字段是模型的字段，不是时返回错误
+func (r *GormStore[M]) ExtractCriteria(source any) (*Criteria, error) {
+	return extractCriteria(source, func(field string) (string, error) {
+		column, err := r.modelColumn(field)
+		if err != nil {
+			return "", err
+		}
+		if i := strings.LastIndex(field, "."); i >= 0 {
+			return field[:i+1] + column.Name, nil
+		}
+		return column.Name, nil
+	})
+}
+
 func (r *GormStore[M]) ScopeClosure(closure gormClosure) *GormStore[M] {
-	nr := r.onceClone()
+	nr := r.clone()
 	nr.scopeClosures = append(nr.scopeClosures, closure)
 	return nr
 }
 
 func (r *GormStore[M]) AddPreload(name string, args ...any) *GormStore[M] {
-	nr := r.onceClone()
+	nr := r.clone()
 	nr.scopeClosures = append(nr.scopeClosures, func(tx *gorm.DB) *gorm.DB {
 		return tx.Preload(name, args...)
 	})
@@ -340,22 +424,37 @@ func (r *GormStore[M]) AddPreload(name string, args ...any) *GormStore[M] {
 	return nr
 }
 
-func (r *GormStore[M]) present(ctx context.Context, criteria *Criteria) *gorm.DB {
-	var db *gorm.DB
-	if r.tx != nil {
-		db = r.tx.WithContext(ctx)
-	} else {
-		db = r.db.WithContext(ctx)
+// parseSchema 解析模型 M 的 gorm schema
+func (r *GormStore[M]) parseSchema() (*schema.Schema, error) {
+	var model M
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(&model); err != nil {
+		return nil, err
 	}
+	return stmt.Schema, nil
+}
 
-	// 创建本地副本，避免修改原始对象
-	var localScopeClosures []gormClosure
-	if len(r.scopeClosures) > 0 {
-		localScopeClosures = append(localScopeClosures, r.scopeClosures...)
-	}
+// errorDB 返回带有错误的 *gorm.DB，用于在执行前就失败的写操作
+func (r *GormStore[M]) errorDB(ctx context.Context, err error) *gorm.DB {
+	// 不经过 present，避免租户等条件再次产生错误
+	db := r.primary(ctx)
+	_ = db.AddError(err)
+	return db
+}
 
+// present 基于主库构建查询，用于写操作
+func (r *GormStore[M]) present(ctx context.Context, criteria *Criteria) *gorm.DB {
+	return r.presentOn(ctx, r.primary(ctx), criteria)
+}
+
+// presentRead 基于从库构建查询，事务中或 ForcePrimary 时使用主库
+func (r *GormStore[M]) presentRead(ctx context.Context, criteria *Criteria) *gorm.DB {
+	return r.presentOn(ctx, r.replica(ctx), criteria)
+}
+
+func (r *GormStore[M]) presentOn(ctx context.Context, db *gorm.DB, criteria *Criteria) *gorm.DB {
 	if len(r.hidden) > 0 {
-		db = db.Omit(r.hidden...)
+		db = db.Omit(r.omitColumns()...)
 	}
 	if len(r.columns) > 0 {
 		db = db.Select(r.columns)
@@ -363,40 +462,41 @@ func (r *GormStore[M]) present(ctx context.Context, criteria *Criteria) *gorm.DB
 	if r.unscoped {
 		db = db.Unscoped()
 	}
+	for _, closure := range r.scopeClosures {
+		db = closure(db)
+	}
+	if len(r.scopes) > 0 {
+		db = db.Set(scopesSettingKey, r.scopes)
+	}
+	if criteria != nil && criteria.opaque {
+		// WhereHas 等关联条件在查询时解析模型的关联关系
+		db = db.Set(schemaSettingKey, r.parseSchema)
+	}
 	if criteria != nil {
-		if criteria.GetOffset() > 0 {
-			db = db.Offset(criteria.GetOffset())
-		}
-		// 有 offset 一定要有 limit
-		if criteria.limit > 0 || criteria.GetOffset() > 0 {
-			db = db.Limit(criteria.limit)
-		}
-		if criteria.group != "" {
-			db = db.Group(criteria.group)
-		}
-		for _, item := range criteria.orders {
-			db = db.Order(item)
-		}
-		// 使用本地副本而不是直接修改 r.scopeClosures
-		if len(criteria.scopeClosures) > 0 {
-			localScopeClosures = append(localScopeClosures, criteria.scopeClosures...)
-		}
+		db = criteria.apply(db)
 	}
-
-	// 使用本地副本
-	if len(localScopeClosures) > 0 {
-		for _, closure := range localScopeClosures {
-			db = closure(db)
+	// 软删除和租户条件在最后添加，与前面的全部条件使用 AND 连接
+	if r.onlyTrashed {
+		field, err := r.softDeleteField()
+		if err != nil {
+			_ = db.AddError(err)
+		} else {
+			db = r.scopeTrashed(db, field)
 		}
 	}
-	return db
+	return r.scopeTenant(ctx, db)
 }
 
-func (r *GormStore[M]) onceClone() *GormStore[M] {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
+// clone 复制当前 store，返回的实例与 r 不共享任何 slice
+func (r *GormStore[M]) clone() *GormStore[M] {
 	newStore := New[M](r.db)
+	newStore.options = r.options
+	if len(r.hooks) > 0 {
+		newStore.hooks = make(map[HookEvent][]Hook[M], len(r.hooks))
+		for event, hooks := range r.hooks {
+			newStore.hooks[event] = append([]Hook[M](nil), hooks...)
+		}
+	}
 	if len(r.scopeClosures) > 0 {
 		newStore.scopeClosures = append(newStore.scopeClosures, r.scopeClosures...)
 	}
@@ -407,26 +507,24 @@ func (r *GormStore[M]) onceClone() *GormStore[M] {
 		newStore.columns = append(newStore.columns, r.columns...)
 	}
 	newStore.unscoped = r.unscoped
+	newStore.withoutTenant = r.withoutTenant
+	newStore.forcePrimary = r.forcePrimary
+	newStore.onlyTrashed = r.onlyTrashed
+	// RegisterScope 修改时会复制 map，这里可以共享
+	newStore.scopes = r.scopes
+	if len(r.relationAggs) > 0 {
+		newStore.relationAggs = append(newStore.relationAggs, r.relationAggs...)
+	}
 	newStore.tx = r.tx
 
 	return newStore
 }
 
-func (r *GormStore[M]) reset() *GormStore[M] {
-	r.columns = nil
-	r.hidden = nil
-	r.scopeClosures = nil
-	r.unscoped = false
-	r.tx = nil
-
-	return r
-}
-
 func (r *GormStore[M]) addColumns(columns []string) *GormStore[M] {
 	if len(columns) == 0 {
 		return r
 	}
-	nr := r.onceClone()
+	nr := r.clone()
 	nr.columns = append(nr.columns, columns...)
 
 	return nr
@@ -436,7 +534,7 @@ func (r *GormStore[M]) addHiddenColumns(columns []string) *GormStore[M] {
 	if len(columns) == 0 {
 		return r
 	}
-	nr := r.onceClone()
+	nr := r.clone()
 	nr.hidden = append(nr.hidden, columns...)
 
 	return nr
@@ -3,6 +3,7 @@ package storeit
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -572,25 +573,80 @@ func TestGormStore_Emit(t *testing.T) {
 	assert.Empty(t, found.Email)
 }
 
-func TestGormStore_Reset(t *testing.T) {
+func TestGormStore_Immutable(t *testing.T) {
 	db := setupTestDB(t)
 	store := New[TestModel](db)
+	ctx := context.Background()
+
+	err := store.Creates(ctx, []TestModel{
+		{Name: "User 1", Age: 20, Email: "user1@test.com"},
+		{Name: "User 2", Age: 25, Email: "user2@test.com"},
+	}).Error
+	assert.NoError(t, err)
 
-	// 添加一些设置
-	store = store.
+	// 构造方法返回新实例，不修改原 store
+	scoped := store.
 		Columns([]string{"name"}).
 		Hidden([]string{"email"}).
 		ScopeClosure(func(tx *gorm.DB) *gorm.DB {
 			return tx.Where("age > ?", 20)
 		})
-
-	// 测试重置
-	store = store.reset()
 	assert.Empty(t, store.columns)
 	assert.Empty(t, store.hidden)
 	assert.Empty(t, store.scopeClosures)
-	assert.False(t, store.unscoped)
+
+	// 查询方法不重置状态，可以重复使用
+	for i := 0; i < 2; i++ {
+		found, err := scoped.Find(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(found))
+		assert.Equal(t, "User 2", found[0].Name)
+		assert.Zero(t, found[0].Age)
+	}
+	assert.Equal(t, []string{"name"}, scoped.columns)
+	assert.Len(t, scoped.scopeClosures, 1)
+
+	tx := db.Begin()
+	txStore := store.SetTx(tx)
+	_, err = txStore.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tx, txStore.tx)
 	assert.Nil(t, store.tx)
+	tx.Rollback()
+}
+
+func TestGormStore_ConcurrentShared(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	var models []TestModel
+	for i := 0; i < 20; i++ {
+		models = append(models, TestModel{Name: fmt.Sprintf("User %d", i), Age: i})
+	}
+	err := store.Creates(ctx, models).Error
+	assert.NoError(t, err)
+
+	scoped := store.ScopeClosure(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("age >= ?", 10)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pagination, err := scoped.Paginate(ctx, NewCriteria().Page(1).PerPage(5))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(10), pagination.Total)
+			assert.Equal(t, 5, len(pagination.Items))
+
+			count, err := store.Columns([]string{"id"}).Count(ctx, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(20), count)
+		}()
+	}
+	wg.Wait()
 }
 
 func TestGormStore_Present(t *testing.T) {
@@ -903,3 +959,102 @@ func TestGormStore_UpdatesById(t *testing.T) {
 	assert.Equal(t, 45, found.Age)                      // 已更新
 	assert.Equal(t, "updated@example.com", found.Email) // 保持不变
 }
+
+func TestGormStore_ExtractCriteriaOperators(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	models := []TestModel{
+		{Name: "User 1", Age: 20, Email: "user1@test.com"},
+		{Name: "User 2", Age: 25},
+		{Name: "User 3", Age: 30, Email: "user3@test.com"},
+	}
+	err := store.Creates(ctx, models).Error
+	assert.NoError(t, err)
+
+	type req struct {
+		Names   string `criteria:"name:notin"`
+		Ages    []int  `criteria:"age:in"`
+		Between []int  `criteria:"age:between"`
+		NoEmail bool   `criteria:"email:notnull"`
+		Sort    string `criteria:"-:sort"`
+	}
+	criteria, err := ExtractCriteria(req{
+		Names:   "User 3",
+		Ages:    []int{20, 25, 30},
+		Between: []int{18, 30},
+		NoEmail: true,
+		Sort:    "age-",
+	})
+	assert.NoError(t, err)
+
+	found, err := store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(found))
+	assert.Equal(t, "User 2", found[0].Name)
+
+	// isnull 过滤掉所有非 NULL 记录
+	type nullReq struct {
+		Email bool `criteria:"email:isnull"`
+	}
+	criteria, err = ExtractCriteria(nullReq{Email: true})
+	assert.NoError(t, err)
+	found, err = store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+
+	// 值为 false 时使用相反的条件
+	type optionalNullReq struct {
+		Email *bool `criteria:"email:isnull"`
+	}
+	no := false
+	criteria, err = ExtractCriteria(optionalNullReq{Email: &no})
+	assert.NoError(t, err)
+	found, err = store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+	criteria, err = ExtractCriteria(optionalNullReq{})
+	assert.NoError(t, err)
+	assert.Empty(t, criteria.Conditions())
+}
+
+func TestGormStore_ExtractCriteria(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Create(ctx, &TestModel{Name: "foo", Age: 20, Email: "foo@example.com"}).Error)
+	assert.NoError(t, store.Create(ctx, &TestModel{Name: "bar", Age: 30}).Error)
+
+	type Search struct {
+		Keyword string `criteria:"name, test_models.email:like"`
+		Sort    string `criteria:"-:sort"`
+	}
+	criteria, err := store.ExtractCriteria(Search{Keyword: "foo", Sort: "age-,CreatedAt"})
+	assert.NoError(t, err)
+	found, err := store.Find(ctx, criteria)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// 过滤字段和排序字段必须是模型的字段
+	_, err = store.ExtractCriteria(Search{Sort: "password"})
+	assert.ErrorContains(t, err, "password")
+	type BadFilter struct {
+		Keyword string `criteria:"name,password:like"`
+	}
+	_, err = store.ExtractCriteria(BadFilter{Keyword: "foo"})
+	assert.ErrorContains(t, err, "password")
+	type OtherTable struct {
+		Name string `criteria:"users.name:eq"`
+	}
+	_, err = store.ExtractCriteria(OtherTable{Name: "foo"})
+	assert.Error(t, err)
+
+	// sortable 优先于模型字段
+	type Sortable struct {
+		Sort string `criteria:"-:sort" sortable:"name"`
+	}
+	_, err = store.ExtractCriteria(Sortable{Sort: "age"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package storeit
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// SubqueryStore 可以作为子查询的 store，GormStore 实现了该接口
+type SubqueryStore interface {
+	subquery(tx *gorm.DB, criteria *Criteria, column string) (*gorm.DB, error)
+}
+
+// subquery 在外层查询的连接上构建子查询，store 的软删除、多租户等条件同样生效。
+// column 不为空时只查询该列，column 必须是模型的字段。
+func (r *GormStore[M]) subquery(tx *gorm.DB, criteria *Criteria, column string) (*gorm.DB, error) {
+	var model M
+	db := r.presentOn(tx.Statement.Context, tx.Session(&gorm.Session{NewDB: true}), criteria).Model(&model)
+	// 嵌入外层查询的子查询的错误不会传递到外层查询，需要在这里返回
+	if db.Error != nil {
+		return nil, db.Error
+	}
+	if column == "" {
+		return db, nil
+	}
+	col, err := r.modelColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	return db.Select("?", col), nil
+}
+
+// WhereInSubquery 添加 field IN (SELECT column FROM ...) 条件，子查询由 store 和 criteria 构建
+func (c *Criteria) WhereInSubquery(field string, store SubqueryStore, criteria *Criteria, column string) *Criteria {
+	if !identifierRegexp.MatchString(field) {
+		return c.addError(fmt.Errorf("invalid criteria field: %s", field))
+	}
+	return c.subqueryClosure(store, criteria, column, func(tx, sub *gorm.DB) *gorm.DB {
+		return tx.Where(fmt.Sprintf("%s IN (?)", QuoteIdentifier(tx, field)), sub)
+	})
+}
+
+// WhereExists 添加 EXISTS 子查询条件，关联外层查询时在 criteria 中使用带表名的条件，
+// 例如 NewCriteria().Where("orders.user_id = users.id")
+func (c *Criteria) WhereExists(store SubqueryStore, criteria *Criteria) *Criteria {
+	return c.subqueryClosure(store, criteria, "", func(tx, sub *gorm.DB) *gorm.DB {
+		return tx.Where("EXISTS (?)", sub)
+	})
+}
+
+// WhereNotExists 添加 NOT EXISTS 子查询条件，用法同 WhereExists
+func (c *Criteria) WhereNotExists(store SubqueryStore, criteria *Criteria) *Criteria {
+	return c.subqueryClosure(store, criteria, "", func(tx, sub *gorm.DB) *gorm.DB {
+		return tx.Where("NOT EXISTS (?)", sub)
+	})
+}
+
+// subqueryClosure 子查询在查询时构建，包含子查询的 Criteria 不能序列化
+func (c *Criteria) subqueryClosure(store SubqueryStore, criteria *Criteria, column string, apply func(tx, sub *gorm.DB) *gorm.DB) *Criteria {
+	c.scopeClosures = append(c.scopeClosures, func(tx *gorm.DB) *gorm.DB {
+		if tx.Error != nil {
+			return tx
+		}
+		sub, err := store.subquery(tx, criteria, column)
+		if err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+		return apply(tx, sub)
+	})
+	c.opaque = true
+	return c
+}
+
+// addError 添加查询时返回的错误
+func (c *Criteria) addError(err error) *Criteria {
+	c.scopeClosures = append(c.scopeClosures, func(tx *gorm.DB) *gorm.DB {
+		_ = tx.AddError(err)
+		return tx
+	})
+	c.opaque = true
+	return c
+}
@@ -0,0 +1,98 @@
+package storeit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func seedSubqueryModels(t *testing.T, store *GormStore[TestModel], addresses *GormStore[Address]) {
+	ctx := context.Background()
+	models := []TestModel{{Name: "Alice", Age: 20}, {Name: "Bob", Age: 30}, {Name: "Carol", Age: 40}}
+	assert.NoError(t, store.Creates(ctx, models).Error)
+	assert.NoError(t, addresses.Creates(ctx, []Address{
+		{UserId: uint(models[0].ID), Street: "Main St"},
+		{UserId: uint(models[0].ID), Street: "Second St"},
+		{UserId: uint(models[1].ID), Street: "Main St"},
+	}).Error)
+	// 软删除的用户不会出现在子查询中
+	assert.NoError(t, store.Delete(ctx, &models[1]).Error)
+}
+
+func TestCriteria_WhereInSubquery(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&Address{}))
+	store := New[TestModel](db)
+	addresses := New[Address](db)
+	ctx := context.Background()
+	seedSubqueryModels(t, store, addresses)
+
+	found, err := store.Find(ctx, NewCriteria().WhereInSubquery("id", addresses, NewCriteria().Where("street = ?", "Main St"), "user_id"))
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "Alice", found[0].Name)
+
+	// 子查询的 store 条件同样生效
+	streets, err := addresses.Find(ctx, NewCriteria().WhereInSubquery("user_id", store, NewCriteria().WhereGte("age", 20), "id").OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Len(t, streets, 2)
+
+	// 包含子查询的 Criteria 不能序列化
+	_, err = json.Marshal(NewCriteria().WhereInSubquery("id", addresses, nil, "user_id"))
+	assert.Error(t, err)
+
+	// 列名和字段名需要合法
+	_, err = store.Find(ctx, NewCriteria().WhereInSubquery("id", addresses, nil, "user_id) OR (1=1"))
+	assert.Error(t, err)
+	_, err = store.Find(ctx, NewCriteria().WhereInSubquery("id) OR (1=1", addresses, nil, "user_id"))
+	assert.Error(t, err)
+}
+
+func TestCriteria_WhereExists(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&Address{}))
+	store := New[TestModel](db)
+	addresses := New[Address](db)
+	ctx := context.Background()
+	seedSubqueryModels(t, store, addresses)
+
+	// 关联子查询
+	related := NewCriteria().Where("addresses.user_id = test_models.id")
+	found, err := store.Find(ctx, NewCriteria().WhereExists(addresses, related.Clone().Where("street = ?", "Second St")))
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "Alice", found[0].Name)
+
+	found, err = store.Find(ctx, NewCriteria().WhereNotExists(addresses, related).OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "Carol", found[0].Name)
+
+	// 非关联子查询
+	count, err := store.Count(ctx, NewCriteria().WhereExists(addresses, NewCriteria().Where("street = ?", "Nowhere")))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	sql, _, err := NewCriteria().WhereExists(addresses, related).ToSQL(db, TestModel{})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "EXISTS (SELECT * FROM `addresses` WHERE addresses.user_id = test_models.id)")
+}
+
+func TestCriteria_SubqueryError(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&Address{}))
+	store := New[TestModel](db)
+	addresses := New[Address](db)
+	ctx := context.Background()
+	seedSubqueryModels(t, store, addresses)
+
+	// 子查询的错误返回给外层查询
+	_, err := store.Find(ctx, NewCriteria().WhereInSubquery("id", addresses, NewCriteria().Scope("nope"), "user_id"))
+	assert.ErrorContains(t, err, "criteria scope nope not registered")
+	_, err = store.Find(ctx, NewCriteria().WhereExists(addresses, NewCriteria().Scope("nope")))
+	assert.ErrorContains(t, err, "criteria scope nope not registered")
+	_, err = store.Count(ctx, NewCriteria().WhereNotExists(addresses, NewCriteria().Where("street = ?", "Main St").Scope("nope")))
+	assert.ErrorContains(t, err, "criteria scope nope not registered")
+}
@@ -0,0 +1,105 @@
+package storeit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tenantContextKey struct{}
+
+// TenantResolver 从 ctx 中读取租户，第二个返回值表示是否存在租户
+type TenantResolver func(ctx context.Context) (any, bool)
+
+// ContextWithTenant 返回携带租户的 ctx
+func ContextWithTenant(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext 返回 ContextWithTenant 设置的租户
+func TenantFromContext(ctx context.Context) (any, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tenant := ctx.Value(tenantContextKey{})
+	return tenant, tenant != nil
+}
+
+// WithTenant 开启多租户，所有查询、更新、删除都会加上 column = 租户 的条件，
+// 创建时自动填充租户字段。ctx 中没有租户时操作返回 ErrMissingTenant，除非使用 WithoutTenant。
+// 默认通过 TenantFromContext 读取租户，可以用 WithTenantResolver 替换。
+func WithTenant(column string) Option {
+	return func(o *options) {
+		o.tenantColumn = column
+	}
+}
+
+// WithTenantResolver 指定从 ctx 读取租户的方法
+func WithTenantResolver(resolver TenantResolver) Option {
+	return func(o *options) {
+		o.tenantResolver = resolver
+	}
+}
+
+// WithoutTenant 返回不做租户隔离的 store，用于后台任务等跨租户的场景
+func (r *GormStore[M]) WithoutTenant() *GormStore[M] {
+	nr := r.clone()
+	nr.withoutTenant = true
+	return nr
+}
+
+func (r *GormStore[M]) tenant(ctx context.Context) (any, error) {
+	resolver := r.options.tenantResolver
+	if resolver == nil {
+		resolver = TenantFromContext
+	}
+	tenant, ok := resolver(ctx)
+	if !ok {
+		return nil, ErrMissingTenant
+	}
+	return tenant, nil
+}
+
+// scopeTenant 为 db 加上租户条件，db 中已有的条件包含 OR 时先用括号包裹，避免绕过租户条件
+func (r *GormStore[M]) scopeTenant(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if r.options.tenantColumn == "" || r.withoutTenant {
+		return db
+	}
+	tenant, err := r.tenant(ctx)
+	if err != nil {
+		_ = db.AddError(err)
+		return db
+	}
+	return db.Clauses(groupWhere{}).Where(clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: r.options.tenantColumn},
+		Value:  tenant,
+	})
+}
+
+// stampTenant 为新建的模型填充租户字段
+func (r *GormStore[M]) stampTenant(ctx context.Context, models []*M) error {
+	if r.options.tenantColumn == "" || r.withoutTenant {
+		return nil
+	}
+	tenant, err := r.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	s, err := r.parseSchema()
+	if err != nil {
+		return err
+	}
+	field := s.LookUpField(r.options.tenantColumn)
+	if field == nil {
+		return fmt.Errorf("tenant column %s not found in %s", r.options.tenantColumn, s.Name)
+	}
+	for _, model := range models {
+		if err = field.Set(ctx, reflect.ValueOf(model).Elem(), tenant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
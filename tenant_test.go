@@ -0,0 +1,142 @@
+package storeit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TenantModel struct {
+	ID       int    `gorm:"primarykey"`
+	TenantID int    `gorm:"index"`
+	Name     string `gorm:"size:255"`
+}
+
+func TestGormStore_Tenant(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&TenantModel{})
+	assert.NoError(t, err)
+	store := New[TenantModel](db, WithTenant("tenant_id"))
+
+	ctx1 := ContextWithTenant(context.Background(), 1)
+	ctx2 := ContextWithTenant(context.Background(), 2)
+
+	// 创建时自动填充租户
+	model := &TenantModel{Name: "t1 a"}
+	err = store.Create(ctx1, model).Error
+	assert.NoError(t, err)
+	assert.Equal(t, 1, model.TenantID)
+	err = store.Creates(ctx1, []TenantModel{{Name: "t1 b"}, {Name: "t1 c", TenantID: 2}}).Error
+	assert.NoError(t, err)
+	err = store.CreateInBatches(ctx2, []TenantModel{{Name: "t2 a"}}, 10).Error
+	assert.NoError(t, err)
+
+	// 查询只返回当前租户的数据
+	count, err := store.Count(ctx1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	found, err := store.Find(ctx2, nil)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "t2 a", found[0].Name)
+	_, err = store.FindByID(ctx2, model.ID)
+	assert.Error(t, err)
+
+	// 更新、删除不会影响其他租户
+	tx := store.Updates(ctx2, map[string]any{"name": "hacked"}, nil)
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+	tx = store.DeleteById(ctx2, model.ID)
+	assert.NoError(t, tx.Error)
+	assert.Zero(t, tx.RowsAffected)
+
+	// Save 不能覆盖其他租户的数据
+	tx = store.Save(ctx2, TenantModel{ID: model.ID, Name: "stolen"})
+	assert.NoError(t, tx.Error)
+	assert.Zero(t, tx.RowsAffected)
+	found1, err := store.FindByID(ctx1, model.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "t1 a", found1.Name)
+	assert.Equal(t, 1, found1.TenantID)
+
+	// 没有租户时拒绝执行
+	ctx := context.Background()
+	_, err = store.Find(ctx, nil)
+	assert.ErrorIs(t, err, ErrMissingTenant)
+	_, err = store.Count(ctx, nil)
+	assert.ErrorIs(t, err, ErrMissingTenant)
+	err = store.Create(ctx, &TenantModel{Name: "none"}).Error
+	assert.ErrorIs(t, err, ErrMissingTenant)
+	err = store.Deletes(ctx, NewCriteria()).Error
+	assert.ErrorIs(t, err, ErrMissingTenant)
+
+	// WithoutTenant 跨租户
+	count, err = store.WithoutTenant().Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	count, err = store.WithoutTenant().Columns([]string{"id"}).Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+}
+
+func TestGormStore_TenantOrWhere(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&TenantModel{}))
+	store := New[TenantModel](db, WithTenant("tenant_id"))
+	ctx1 := ContextWithTenant(context.Background(), 1)
+	ctx2 := ContextWithTenant(context.Background(), 2)
+	assert.NoError(t, store.Create(ctx1, &TenantModel{Name: "a"}).Error)
+	assert.NoError(t, store.Create(ctx2, &TenantModel{Name: "b"}).Error)
+
+	// OR 条件不能绕过租户条件
+	criteria := func() *Criteria {
+		return NewCriteria().Where("name = ?", "x").OrWhere("name = ?", "b")
+	}
+	found, err := store.Find(ctx1, criteria())
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+	found, err = store.Find(ctx1, NewCriteria().OrGroup(func(g *Criteria) {
+		g.Where("name = ?", "b")
+	}))
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+
+	tx := store.Updates(ctx1, map[string]any{"name": "changed"}, criteria())
+	assert.NoError(t, tx.Error)
+	assert.Zero(t, tx.RowsAffected)
+	tx = store.Deletes(ctx1, criteria())
+	assert.NoError(t, tx.Error)
+	assert.Zero(t, tx.RowsAffected)
+
+	found, err = store.Find(ctx2, criteria())
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "b", found[0].Name)
+}
+
+func TestGormStore_TenantResolver(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&TenantModel{})
+	assert.NoError(t, err)
+
+	type key struct{}
+	store := New[TenantModel](db, WithTenant("tenant_id"), WithTenantResolver(func(ctx context.Context) (any, bool) {
+		tenant, ok := ctx.Value(key{}).(int)
+		return tenant, ok
+	}))
+	ctx := context.WithValue(context.Background(), key{}, 7)
+
+	model := &TenantModel{Name: "a"}
+	err = store.Create(ctx, model).Error
+	assert.NoError(t, err)
+	assert.Equal(t, 7, model.TenantID)
+
+	_, err = store.Find(ContextWithTenant(context.Background(), 7), nil)
+	assert.ErrorIs(t, err, ErrMissingTenant)
+
+	// 租户字段不存在
+	err = New[TestModel](db, WithTenant("tenant_id")).Create(ContextWithTenant(ctx, 7), &TestModel{}).Error
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrMissingTenant)
+}
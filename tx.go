@@ -0,0 +1,79 @@
+package storeit
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+type txContextKey struct{}
+
+type txContext struct {
+	tx          *gorm.DB
+	afterCommit *commitHooks
+}
+
+// commitHooks 最外层事务提交后执行的回调，嵌套的 SAVEPOINT 共用最外层的回调
+type commitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+func (h *commitHooks) add(hook func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, hook)
+}
+
+func (h *commitHooks) run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// Transaction 在事务中执行 fc，事务通过 ctx 传递，GormStore 使用该 ctx 时会自动加入事务。
+// 嵌套调用会使用 SAVEPOINT，fc 返回错误或 panic 时回滚。
+func Transaction(ctx context.Context, db *gorm.DB, fc func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	parent := txContextFrom(ctx)
+	afterCommit := &commitHooks{}
+	if parent != nil {
+		db = parent.tx
+		afterCommit = parent.afterCommit
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fc(context.WithValue(ctx, txContextKey{}, &txContext{tx: tx, afterCommit: afterCommit}))
+	}, opts...)
+	if err == nil && parent == nil {
+		afterCommit.run()
+	}
+	return err
+}
+
+// TxFromContext 返回 ctx 中由 Transaction 开启的事务，不存在时返回 nil
+func TxFromContext(ctx context.Context) *gorm.DB {
+	if tc := txContextFrom(ctx); tc != nil {
+		return tc.tx
+	}
+	return nil
+}
+
+// afterCommit 在 ctx 中的事务提交后执行 hook，ctx 中没有事务时忽略
+func afterCommit(ctx context.Context, hook func()) {
+	if tc := txContextFrom(ctx); tc != nil {
+		tc.afterCommit.add(hook)
+	}
+}
+
+func txContextFrom(ctx context.Context) *txContext {
+	if ctx == nil {
+		return nil
+	}
+	tc, _ := ctx.Value(txContextKey{}).(*txContext)
+	return tc
+}
@@ -0,0 +1,98 @@
+package storeit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	err := Transaction(ctx, db, func(ctx context.Context) error {
+		assert.NotNil(t, TxFromContext(ctx))
+		return store.Create(ctx, &TestModel{Name: "User 1", Age: 20}).Error
+	})
+	assert.NoError(t, err)
+
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestTransaction_Rollback(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	rollbackErr := errors.New("rollback")
+	err := Transaction(ctx, db, func(ctx context.Context) error {
+		err := store.Create(ctx, &TestModel{Name: "User 1", Age: 20}).Error
+		assert.NoError(t, err)
+		// 事务内可以读到未提交的数据
+		count, err := store.Count(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		return rollbackErr
+	})
+	assert.ErrorIs(t, err, rollbackErr)
+
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestTransaction_Panic(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	assert.Panics(t, func() {
+		_ = Transaction(ctx, db, func(ctx context.Context) error {
+			_ = store.Create(ctx, &TestModel{Name: "User 1", Age: 20})
+			panic("boom")
+		})
+	})
+
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestTransaction_NestedSavepoint(t *testing.T) {
+	db := setupTestDB(t)
+	store := New[TestModel](db)
+	ctx := context.Background()
+
+	err := Transaction(ctx, db, func(ctx context.Context) error {
+		if err := store.Create(ctx, &TestModel{Name: "Outer", Age: 20}).Error; err != nil {
+			return err
+		}
+		// 内层失败只回滚到 savepoint
+		innerErr := Transaction(ctx, db, func(ctx context.Context) error {
+			if err := store.Create(ctx, &TestModel{Name: "Inner", Age: 30}).Error; err != nil {
+				return err
+			}
+			return errors.New("inner failed")
+		})
+		assert.Error(t, innerErr)
+
+		return Transaction(ctx, db, func(ctx context.Context) error {
+			return store.Create(ctx, &TestModel{Name: "Inner OK", Age: 40}).Error
+		})
+	})
+	assert.NoError(t, err)
+
+	var names []string
+	err = store.Pluck(ctx, "name", &names, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Outer", "Inner OK"}, names)
+}
+
+func TestTxFromContext(t *testing.T) {
+	assert.Nil(t, TxFromContext(context.Background()))
+}
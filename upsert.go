@@ -0,0 +1,89 @@
+package storeit
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertOption 配置 Upsert 冲突时的行为
+type UpsertOption func(*clause.OnConflict)
+
+// UpsertDoNothing 冲突时忽略该记录
+func UpsertDoNothing() UpsertOption {
+	return func(c *clause.OnConflict) {
+		c.DoNothing = true
+	}
+}
+
+// UpsertUpdateAll 冲突时更新除主键和 created_at 等自动创建时间以外的所有字段
+func UpsertUpdateAll() UpsertOption {
+	return func(c *clause.OnConflict) {
+		c.UpdateAll = true
+	}
+}
+
+// Upsert 插入记录，conflictColumns 冲突时更新 updateColumns，由 gorm 根据数据库生成
+// ON CONFLICT 或 ON DUPLICATE KEY UPDATE 语句（MySQL 忽略 conflictColumns）。
+// 没有 updateColumns 时需要使用 UpsertDoNothing 或 UpsertUpdateAll。Upsert 会触发 create 钩子。
+// 开启多租户时冲突的记录属于其他租户不会更新，MySQL 的 ON DUPLICATE KEY UPDATE 不支持该条件，
+// 因此 MySQL 上开启多租户的 store 只能使用 UpsertDoNothing。
+func (r *GormStore[M]) Upsert(ctx context.Context, models []M, conflictColumns []string, updateColumns []string, opts ...UpsertOption) *gorm.DB {
+	return r.upsert(ctx, models, conflictColumns, updateColumns, 0, opts)
+}
+
+// UpsertInBatches 分批执行 Upsert
+func (r *GormStore[M]) UpsertInBatches(ctx context.Context, models []M, conflictColumns []string, updateColumns []string, batchSize int, opts ...UpsertOption) *gorm.DB {
+	return r.upsert(ctx, models, conflictColumns, updateColumns, batchSize, opts)
+}
+
+func (r *GormStore[M]) upsert(ctx context.Context, models []M, conflictColumns []string, updateColumns []string, batchSize int, opts []UpsertOption) *gorm.DB {
+	onConflict, err := r.onConflict(ctx, conflictColumns, updateColumns, opts)
+	if err != nil {
+		return r.errorDB(ctx, err)
+	}
+	scope := &HookScope[M]{Models: modelPointers(models)}
+	return r.write(ctx, HookBeforeCreate, HookAfterCreate, scope, func() *gorm.DB {
+		if err := r.stampTenant(ctx, scope.Models); err != nil {
+			return r.errorDB(ctx, err)
+		}
+		db := r.present(ctx, nil).Clauses(onConflict)
+		if batchSize > 0 {
+			return db.CreateInBatches(&models, batchSize)
+		}
+		return db.Create(&models)
+	})
+}
+
+func (r *GormStore[M]) onConflict(ctx context.Context, conflictColumns []string, updateColumns []string, opts []UpsertOption) (clause.OnConflict, error) {
+	onConflict := clause.OnConflict{}
+	for _, column := range conflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	for _, opt := range opts {
+		opt(&onConflict)
+	}
+	if !onConflict.DoNothing && !onConflict.UpdateAll {
+		if len(updateColumns) == 0 {
+			return onConflict, errors.New("upsert update columns is empty")
+		}
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	}
+	// 冲突的记录属于其他租户时不更新
+	if r.options.tenantColumn != "" && !r.withoutTenant && !onConflict.DoNothing {
+		tenant, err := r.tenant(ctx)
+		if err != nil {
+			return onConflict, err
+		}
+		if r.primary(ctx).Dialector.Name() == "mysql" {
+			return onConflict, errors.New("upsert with tenant can not update on conflict in mysql, use UpsertDoNothing instead")
+		}
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: r.options.tenantColumn},
+			Value:  tenant,
+		}}}
+	}
+	return onConflict, nil
+}
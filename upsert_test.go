@@ -0,0 +1,129 @@
+package storeit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type UpsertModel struct {
+	ID        int    `gorm:"primarykey"`
+	TenantID  int    `gorm:"uniqueIndex:idx_tenant_code"`
+	Code      string `gorm:"size:64;uniqueIndex:idx_tenant_code"`
+	Name      string `gorm:"size:255"`
+	Stock     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func TestGormStore_Upsert(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&UpsertModel{})
+	assert.NoError(t, err)
+	store := New[UpsertModel](db)
+	ctx := context.Background()
+	conflict := []string{"tenant_id", "code"}
+
+	err = store.Upsert(ctx, []UpsertModel{{Code: "a", Name: "A", Stock: 1}, {Code: "b", Name: "B", Stock: 1}}, conflict, []string{"stock"}).Error
+	assert.NoError(t, err)
+
+	// 只更新指定的字段
+	err = store.Upsert(ctx, []UpsertModel{{Code: "a", Name: "A2", Stock: 5}, {Code: "c", Name: "C", Stock: 1}}, conflict, []string{"stock"}).Error
+	assert.NoError(t, err)
+	found, err := store.First(ctx, NewCriteria().Where("code = ?", "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "A", found.Name)
+	assert.Equal(t, 5, found.Stock)
+	createdAt := found.CreatedAt
+
+	// DO NOTHING
+	err = store.Upsert(ctx, []UpsertModel{{Code: "a", Name: "A3", Stock: 9}}, conflict, nil, UpsertDoNothing()).Error
+	assert.NoError(t, err)
+	found, err = store.First(ctx, NewCriteria().Where("code = ?", "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, found.Stock)
+
+	// 更新所有字段，但保留 created_at
+	err = store.UpsertInBatches(ctx, []UpsertModel{
+		{Code: "a", Name: "A4", Stock: 7, CreatedAt: time.Now().Add(time.Hour)},
+		{Code: "d", Name: "D", Stock: 1},
+	}, conflict, nil, 1, UpsertUpdateAll()).Error
+	assert.NoError(t, err)
+	found, err = store.First(ctx, NewCriteria().Where("code = ?", "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "A4", found.Name)
+	assert.Equal(t, 7, found.Stock)
+	assert.True(t, createdAt.Equal(found.CreatedAt))
+
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	// 没有更新字段
+	err = store.Upsert(ctx, []UpsertModel{{Code: "e"}}, conflict, nil).Error
+	assert.Error(t, err)
+}
+
+func TestGormStore_UpsertTenant(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&UpsertModel{})
+	assert.NoError(t, err)
+	store := New[UpsertModel](db, WithTenant("tenant_id"))
+	ctx1 := ContextWithTenant(context.Background(), 1)
+	ctx2 := ContextWithTenant(context.Background(), 2)
+
+	err = store.Upsert(ctx1, []UpsertModel{{Code: "a", Stock: 1}}, []string{"tenant_id", "code"}, []string{"stock"}).Error
+	assert.NoError(t, err)
+	err = store.Upsert(ctx2, []UpsertModel{{Code: "a", Stock: 2}}, []string{"tenant_id", "code"}, []string{"stock"}).Error
+	assert.NoError(t, err)
+	err = store.Upsert(ctx1, []UpsertModel{{Code: "a", Stock: 3}}, []string{"tenant_id", "code"}, []string{"stock"}).Error
+	assert.NoError(t, err)
+
+	found, err := store.First(ctx1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, found.Stock)
+	found, err = store.First(ctx2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, found.Stock)
+
+	// 主键冲突的记录属于其他租户时不更新
+	err = store.Upsert(ctx2, []UpsertModel{{ID: found.ID - 1, Code: "x", Stock: 100}}, []string{"id"}, nil, UpsertUpdateAll()).Error
+	assert.NoError(t, err)
+	found, err = store.First(ctx1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, found.Stock)
+	assert.Equal(t, "a", found.Code)
+
+	err = store.Upsert(context.Background(), []UpsertModel{{Code: "b"}}, []string{"tenant_id", "code"}, []string{"stock"}).Error
+	assert.ErrorIs(t, err, ErrMissingTenant)
+
+	// MySQL 的 ON DUPLICATE KEY UPDATE 不能限制租户，只允许 DoNothing
+	mysqlStore := New[UpsertModel](setupMySQLLikeDB(t, db), WithTenant("tenant_id"))
+	err = mysqlStore.Upsert(ctx1, []UpsertModel{{Code: "a", Stock: 4}}, []string{"tenant_id", "code"}, []string{"stock"}).Error
+	assert.ErrorContains(t, err, "mysql")
+	err = mysqlStore.WithoutTenant().Upsert(ctx1, []UpsertModel{{TenantID: 1, Code: "c", Stock: 4}}, []string{"tenant_id", "code"}, []string{"stock"}).Error
+	assert.NoError(t, err)
+}
+
+// mysqlLikeDialector 使用 sqlite 执行，Name 返回 mysql
+type mysqlLikeDialector struct {
+	gorm.Dialector
+}
+
+func (mysqlLikeDialector) Name() string {
+	return "mysql"
+}
+
+// setupMySQLLikeDB 复用 db 的连接，方言名称为 mysql
+func setupMySQLLikeDB(t *testing.T, db *gorm.DB) *gorm.DB {
+	mysqlDB, err := gorm.Open(mysqlLikeDialector{db.Dialector}, &gorm.Config{
+		ConnPool: db.ConnPool,
+		Logger:   logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	return mysqlDB
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+
+	"gorm.io/gorm"
 )
 
 var mysqlReservedWords = []string{
@@ -43,10 +45,90 @@ var mysqlReservedWords = []string{
 	"WRITE", "XOR", "YEAR_MONTH", "ZEROFILL", "RANK", "OFFSET",
 }
 
+var postgresReservedWords = []string{
+	"ALL", "ANALYSE", "ANALYZE", "AND", "ANY", "ARRAY", "AS", "ASC", "ASYMMETRIC",
+	"AUTHORIZATION", "BINARY", "BOTH", "CASE", "CAST", "CHECK", "COLLATE",
+	"COLLATION", "COLUMN", "CONCURRENTLY", "CONSTRAINT", "CREATE", "CROSS",
+	"CURRENT_CATALOG", "CURRENT_DATE", "CURRENT_ROLE", "CURRENT_SCHEMA",
+	"CURRENT_TIME", "CURRENT_TIMESTAMP", "CURRENT_USER", "DEFAULT", "DEFERRABLE",
+	"DESC", "DISTINCT", "DO", "ELSE", "END", "EXCEPT", "FALSE", "FETCH", "FOR",
+	"FOREIGN", "FREEZE", "FROM", "FULL", "GRANT", "GROUP", "HAVING", "ILIKE", "IN",
+	"INITIALLY", "INNER", "INTERSECT", "INTO", "IS", "ISNULL", "JOIN", "LATERAL",
+	"LEADING", "LEFT", "LIKE", "LIMIT", "LOCALTIME", "LOCALTIMESTAMP", "NATURAL",
+	"NOT", "NOTNULL", "NULL", "OFFSET", "ON", "ONLY", "OR", "ORDER", "OUTER",
+	"OVERLAPS", "PLACING", "PRIMARY", "REFERENCES", "RETURNING", "RIGHT", "SELECT",
+	"SESSION_USER", "SIMILAR", "SOME", "SYMMETRIC", "SYSTEM_USER", "TABLE",
+	"TABLESAMPLE", "THEN", "TO", "TRAILING", "TRUE", "UNION", "UNIQUE", "USER",
+	"USING", "VARIADIC", "VERBOSE", "WHEN", "WHERE", "WINDOW", "WITH",
+}
+
+var sqliteReservedWords = []string{
+	"ABORT", "ACTION", "ADD", "AFTER", "ALL", "ALTER", "ALWAYS", "ANALYZE", "AND",
+	"AS", "ASC", "ATTACH", "AUTOINCREMENT", "BEFORE", "BEGIN", "BETWEEN", "BY",
+	"CASCADE", "CASE", "CAST", "CHECK", "COLLATE", "COLUMN", "COMMIT", "CONFLICT",
+	"CONSTRAINT", "CREATE", "CROSS", "CURRENT", "CURRENT_DATE", "CURRENT_TIME",
+	"CURRENT_TIMESTAMP", "DATABASE", "DEFAULT", "DEFERRABLE", "DEFERRED", "DELETE",
+	"DESC", "DETACH", "DISTINCT", "DO", "DROP", "EACH", "ELSE", "END", "ESCAPE",
+	"EXCEPT", "EXCLUDE", "EXCLUSIVE", "EXISTS", "EXPLAIN", "FAIL", "FILTER", "FIRST",
+	"FOLLOWING", "FOR", "FOREIGN", "FROM", "FULL", "GENERATED", "GLOB", "GROUP",
+	"GROUPS", "HAVING", "IF", "IGNORE", "IMMEDIATE", "IN", "INDEX", "INDEXED",
+	"INITIALLY", "INNER", "INSERT", "INSTEAD", "INTERSECT", "INTO", "IS", "ISNULL",
+	"JOIN", "KEY", "LAST", "LEFT", "LIKE", "LIMIT", "MATCH", "MATERIALIZED",
+	"NATURAL", "NO", "NOT", "NOTHING", "NOTNULL", "NULL", "NULLS", "OF", "OFFSET",
+	"ON", "OR", "ORDER", "OTHERS", "OUTER", "OVER", "PARTITION", "PLAN", "PRAGMA",
+	"PRECEDING", "PRIMARY", "QUERY", "RAISE", "RANGE", "RECURSIVE", "REFERENCES",
+	"REGEXP", "REINDEX", "RELEASE", "RENAME", "REPLACE", "RESTRICT", "RETURNING",
+	"RIGHT", "ROLLBACK", "ROW", "ROWS", "SAVEPOINT", "SELECT", "SET", "TABLE",
+	"TEMP", "TEMPORARY", "THEN", "TIES", "TO", "TRANSACTION", "TRIGGER", "UNBOUNDED",
+	"UNION", "UNIQUE", "UPDATE", "USING", "VACUUM", "VALUES", "VIEW", "VIRTUAL",
+	"WHEN", "WHERE", "WINDOW", "WITH", "WITHOUT",
+}
+
+var sqlserverReservedWords = []string{
+	"ADD", "ALL", "ALTER", "AND", "ANY", "AS", "ASC", "AUTHORIZATION", "BACKUP",
+	"BEGIN", "BETWEEN", "BREAK", "BROWSE", "BULK", "BY", "CASCADE", "CASE", "CHECK",
+	"CHECKPOINT", "CLOSE", "CLUSTERED", "COALESCE", "COLLATE", "COLUMN", "COMMIT",
+	"COMPUTE", "CONSTRAINT", "CONTAINS", "CONTAINSTABLE", "CONTINUE", "CONVERT",
+	"CREATE", "CROSS", "CURRENT", "CURRENT_DATE", "CURRENT_TIME",
+	"CURRENT_TIMESTAMP", "CURRENT_USER", "CURSOR", "DATABASE", "DBCC", "DEALLOCATE",
+	"DECLARE", "DEFAULT", "DELETE", "DENY", "DESC", "DISK", "DISTINCT",
+	"DISTRIBUTED", "DOUBLE", "DROP", "DUMP", "ELSE", "END", "ERRLVL", "ESCAPE",
+	"EXCEPT", "EXEC", "EXECUTE", "EXISTS", "EXIT", "EXTERNAL", "FETCH", "FILE",
+	"FILLFACTOR", "FOR", "FOREIGN", "FREETEXT", "FREETEXTTABLE", "FROM", "FULL",
+	"FUNCTION", "GOTO", "GRANT", "GROUP", "HAVING", "HOLDLOCK", "IDENTITY",
+	"IDENTITY_INSERT", "IDENTITYCOL", "IF", "IN", "INDEX", "INNER", "INSERT",
+	"INTERSECT", "INTO", "IS", "JOIN", "KEY", "KILL", "LEFT", "LIKE", "LINENO",
+	"LOAD", "MERGE", "NATIONAL", "NOCHECK", "NONCLUSTERED", "NOT", "NULL", "NULLIF",
+	"OF", "OFF", "OFFSETS", "ON", "OPEN", "OPENDATASOURCE", "OPENQUERY",
+	"OPENROWSET", "OPENXML", "OPTION", "OR", "ORDER", "OUTER", "OVER", "PERCENT",
+	"PIVOT", "PLAN", "PRECISION", "PRIMARY", "PRINT", "PROC", "PROCEDURE",
+	"PUBLIC", "RAISERROR", "READ", "READTEXT", "RECONFIGURE", "REFERENCES",
+	"REPLICATION", "RESTORE", "RESTRICT", "RETURN", "REVERT", "REVOKE", "RIGHT",
+	"ROLLBACK", "ROWCOUNT", "ROWGUIDCOL", "RULE", "SAVE", "SCHEMA",
+	"SECURITYAUDIT", "SELECT", "SEMANTICKEYPHRASETABLE",
+	"SEMANTICSIMILARITYDETAILSTABLE", "SEMANTICSIMILARITYTABLE", "SESSION_USER",
+	"SET", "SETUSER", "SHUTDOWN", "SOME", "STATISTICS", "SYSTEM_USER", "TABLE",
+	"TABLESAMPLE", "TEXTSIZE", "THEN", "TO", "TOP", "TRAN", "TRANSACTION",
+	"TRIGGER", "TRUNCATE", "TRY_CONVERT", "TSEQUAL", "UNION", "UNIQUE", "UNPIVOT",
+	"UPDATE", "UPDATETEXT", "USE", "USER", "VALUES", "VARYING", "VIEW", "WAITFOR",
+	"WHEN", "WHERE", "WHILE", "WITH", "WRITETEXT",
+}
+
+// dialectReservedWords 以 gorm Dialector 的 Name() 为 key 的保留字列表
+var dialectReservedWords = map[string][]string{
+	"mysql":     mysqlReservedWords,
+	"postgres":  postgresReservedWords,
+	"sqlite":    sqliteReservedWords,
+	"sqlserver": sqlserverReservedWords,
+}
+
 // 使用 map 存储保留字，提高查找效率
 var (
 	reservedWordsMap     map[string]bool
 	reservedWordsMapOnce sync.Once
+
+	dialectReservedWordsMap     map[string]map[string]bool
+	dialectReservedWordsMapOnce sync.Once
 )
 
 // 初始化保留字 map
@@ -94,3 +176,49 @@ func QuoteReservedWord(word string) string {
 	}
 	return word
 }
+
+// IsReservedWord 检查一个词是否是指定数据库的保留字，dialect 为 gorm Dialector 的 Name()，
+// 未知的数据库使用 MySQL 的保留字
+func IsReservedWord(dialect, word string) bool {
+	dialectReservedWordsMapOnce.Do(func() {
+		dialectReservedWordsMap = make(map[string]map[string]bool, len(dialectReservedWords))
+		for name, words := range dialectReservedWords {
+			m := make(map[string]bool, len(words))
+			for _, w := range words {
+				m[w] = true
+			}
+			dialectReservedWordsMap[name] = m
+		}
+	})
+	words, ok := dialectReservedWordsMap[dialect]
+	if !ok {
+		return IsMySQLReservedWord(word)
+	}
+	return words[strings.ToUpper(word)]
+}
+
+// QuoteIdentifier 使用 db 的 Dialector 转义字段名中的保留字，支持 表名.列名 的形式，已经转义的部分保持不变。
+// db 为空时使用 QuoteReservedWord。
+func QuoteIdentifier(db *gorm.DB, word string) string {
+	if db == nil || db.Dialector == nil {
+		return QuoteReservedWord(word)
+	}
+	parts := strings.Split(word, ".")
+	for i, part := range parts {
+		if part == "" || isQuoted(part) || !IsReservedWord(db.Dialector.Name(), part) {
+			continue
+		}
+		var builder strings.Builder
+		db.Dialector.QuoteTo(&builder, part)
+		parts[i] = builder.String()
+	}
+	return strings.Join(parts, ".")
+}
+
+func isQuoted(word string) bool {
+	if len(word) < 2 {
+		return false
+	}
+	first, last := word[0], word[len(word)-1]
+	return (first == '`' && last == '`') || (first == '"' && last == '"') || (first == '[' && last == ']')
+}
@@ -5,6 +5,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
 )
 
 func TestIsMySQLReservedWord(t *testing.T) {
@@ -212,3 +216,52 @@ func BenchmarkQuoteReservedWord(b *testing.B) {
 		QuoteReservedWord("order.group")
 	}
 }
+
+// postgresLikeDialector 使用 sqlite 执行，但按 PostgreSQL 的方言转义
+type postgresLikeDialector struct {
+	gorm.Dialector
+}
+
+func (postgresLikeDialector) Name() string {
+	return "postgres"
+}
+
+func (postgresLikeDialector) QuoteTo(writer clause.Writer, str string) {
+	_ = writer.WriteByte('"')
+	_, _ = writer.WriteString(str)
+	_ = writer.WriteByte('"')
+}
+
+func setupPostgresLikeDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgresLikeDialector{sqlite.Open("file::memory:")}, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	return db
+}
+
+func TestIsReservedWord(t *testing.T) {
+	assert.True(t, IsReservedWord("postgres", "user"))
+	assert.False(t, IsReservedWord("mysql", "user"))
+	assert.True(t, IsReservedWord("sqlserver", "User"))
+	assert.True(t, IsReservedWord("sqlite", "order"))
+	assert.True(t, IsReservedWord("sqlite", "glob"))
+	assert.False(t, IsReservedWord("sqlite", "name"))
+	// 未知的数据库使用 MySQL 的保留字
+	assert.True(t, IsReservedWord("clickhouse", "rank"))
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	db := setupPostgresLikeDB(t)
+	assert.Equal(t, `"user"`, QuoteIdentifier(db, "user"))
+	assert.Equal(t, `"user"."order"`, QuoteIdentifier(db, "user.order"))
+	assert.Equal(t, `users.name`, QuoteIdentifier(db, "users.name"))
+	assert.Equal(t, `"user"`, QuoteIdentifier(db, `"user"`))
+
+	sqliteDB := setupTestDB(t)
+	assert.Equal(t, "`order`", QuoteIdentifier(sqliteDB, "order"))
+	assert.Equal(t, "user", QuoteIdentifier(sqliteDB, "user"))
+
+	// db 为空时使用 MySQL 的规则
+	assert.Equal(t, "`order`", QuoteIdentifier(nil, "order"))
+}
@@ -0,0 +1,115 @@
+package storeit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// WithVersionColumn 指定乐观锁版本字段，未指定时使用带有 `storeit:"version"` tag 的字段
+func WithVersionColumn(column string) Option {
+	return func(o *options) {
+		o.versionColumn = column
+	}
+}
+
+// versionField 返回模型的乐观锁版本字段，没有配置时返回 nil
+func (r *GormStore[M]) versionField() (*schema.Field, error) {
+	s, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	if r.options.versionColumn != "" {
+		field := s.LookUpField(r.options.versionColumn)
+		if field == nil || field.DBName == "" {
+			return nil, fmt.Errorf("version column %s not found in %s", r.options.versionColumn, s.Name)
+		}
+		return field, nil
+	}
+	for _, field := range s.Fields {
+		if _, ok := schema.ParseTagSetting(field.Tag.Get("storeit"), ";")["VERSION"]; ok && field.DBName != "" {
+			return field, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *GormStore[M]) saveWithVersion(ctx context.Context, field *schema.Field, model *M) *gorm.DB {
+	if r.isNewRecord(ctx, model) {
+		return r.present(ctx, nil).Save(model)
+	}
+	rv := reflect.ValueOf(model).Elem()
+	version, _ := field.ValueOf(ctx, rv)
+	next, err := cast.ToInt64E(version)
+	if err != nil {
+		return r.errorDB(ctx, err)
+	}
+	if err = field.Set(ctx, rv, next+1); err != nil {
+		return r.errorDB(ctx, err)
+	}
+	tx := r.present(ctx, nil).
+		Model(model).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: version}).
+		Select("*").
+		Updates(model)
+	if tx.Error == nil && tx.RowsAffected == 0 {
+		_ = tx.AddError(ErrVersionConflict)
+	}
+	return tx
+}
+
+func (r *GormStore[M]) updatesWithVersion(ctx context.Context, field *schema.Field, db *gorm.DB, updates any) *gorm.DB {
+	values, err := versionedUpdates[M](ctx, field, updates)
+	if err != nil {
+		_ = db.AddError(err)
+		return db
+	}
+	version, checked := values[field.DBName]
+	if checked {
+		db = db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: version})
+	}
+	values[field.DBName] = gorm.Expr("? + 1", clause.Column{Name: field.DBName})
+	tx := db.Updates(values)
+	if checked && tx.Error == nil && tx.RowsAffected == 0 {
+		_ = tx.AddError(ErrVersionConflict)
+	}
+	return tx
+}
+
+// versionedUpdates 将 updates 转换为以列名为 key 的 map，
+// 支持 map[string]any、M 和 *M，结构体只保留非零值字段，与 gorm Updates 的语义一致
+func versionedUpdates[M any](ctx context.Context, field *schema.Field, updates any) (map[string]any, error) {
+	values := make(map[string]any)
+	switch v := updates.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if f := field.Schema.LookUpField(key); f != nil && f.DBName != "" {
+				key = f.DBName
+			}
+			values[key] = value
+		}
+	case M:
+		structUpdates(ctx, field.Schema, reflect.ValueOf(&v).Elem(), values)
+	case *M:
+		structUpdates(ctx, field.Schema, reflect.ValueOf(v).Elem(), values)
+	default:
+		return nil, fmt.Errorf("unsupported updates type %T for versioned model %s", updates, field.Schema.Name)
+	}
+	return values, nil
+}
+
+func structUpdates(ctx context.Context, s *schema.Schema, rv reflect.Value, values map[string]any) {
+	for _, f := range s.Fields {
+		if f.DBName == "" || f.PrimaryKey {
+			continue
+		}
+		if value, zero := f.ValueOf(ctx, rv); !zero {
+			values[f.DBName] = value
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package storeit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type VersionedModel struct {
+	ID      int    `gorm:"primarykey"`
+	Name    string `gorm:"size:255"`
+	Version int    `storeit:"version"`
+}
+
+type RevisionModel struct {
+	ID       int    `gorm:"primarykey"`
+	Name     string `gorm:"size:255"`
+	Revision int64
+}
+
+func TestGormStore_SaveWithVersion(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&VersionedModel{})
+	assert.NoError(t, err)
+	store := New[VersionedModel](db)
+	ctx := context.Background()
+
+	model := VersionedModel{Name: "v", Version: 1}
+	err = store.Save(ctx, model).Error
+	assert.NoError(t, err)
+
+	found, err := store.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, found.Version)
+
+	// 两个请求读取到相同版本
+	first, second := *found, *found
+	first.Name = "first"
+	tx := store.Save(ctx, first)
+	assert.NoError(t, tx.Error)
+	assert.Equal(t, int64(1), tx.RowsAffected)
+
+	second.Name = "second"
+	err = store.Save(ctx, second).Error
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	found, err = store.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", found.Name)
+	assert.Equal(t, 2, found.Version)
+
+	// CheckedStore 保留冲突错误
+	_, err = store.Checked().Save(ctx, second)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestGormStore_UpdatesByIdWithVersion(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&VersionedModel{})
+	assert.NoError(t, err)
+	store := New[VersionedModel](db)
+	ctx := context.Background()
+
+	err = store.Create(ctx, &VersionedModel{Name: "v", Version: 1}).Error
+	assert.NoError(t, err)
+
+	// 带版本的更新
+	err = store.UpdatesById(ctx, 1, map[string]any{"name": "a", "version": 1}).Error
+	assert.NoError(t, err)
+	err = store.UpdatesById(ctx, 1, map[string]any{"name": "b", "Version": 1}).Error
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	// 结构体更新
+	err = store.UpdatesById(ctx, 1, VersionedModel{Name: "c", Version: 2}).Error
+	assert.NoError(t, err)
+	err = store.UpdatesById(ctx, 1, &VersionedModel{Name: "d", Version: 2}).Error
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	// 不带版本时不校验，但仍然递增版本
+	err = store.UpdatesById(ctx, 1, map[string]any{"name": "e"}).Error
+	assert.NoError(t, err)
+
+	found, err := store.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "e", found.Name)
+	assert.Equal(t, 4, found.Version)
+
+	// 不支持的类型
+	err = store.UpdatesById(ctx, 1, struct{ Name string }{Name: "f"}).Error
+	assert.Error(t, err)
+}
+
+func TestGormStore_WithVersionColumn(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.AutoMigrate(&RevisionModel{})
+	assert.NoError(t, err)
+	store := New[RevisionModel](db, WithVersionColumn("revision"))
+	ctx := context.Background()
+
+	err = store.Create(ctx, &RevisionModel{Name: "r"}).Error
+	assert.NoError(t, err)
+
+	found, err := store.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	found.Name = "updated"
+	err = store.Save(ctx, *found).Error
+	assert.NoError(t, err)
+	err = store.Save(ctx, *found).Error
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	// 克隆后的 store 保留配置
+	err = store.Columns([]string{"name"}).UpdatesById(ctx, 1, map[string]any{"revision": 0}).Error
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	// 不存在的版本字段
+	err = New[RevisionModel](db, WithVersionColumn("missing")).Save(ctx, *found).Error
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package storeit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// relationAgg WithCount 等方法添加的关联聚合列
+type relationAgg struct {
+	relation string
+	fn       AggFunc
+	column   string
+	criteria *Criteria
+}
+
+// WithCount 返回查询时附带关联记录数量的新 store，数量作为子查询列 <relation>_count 返回，
+// 例如 Orders 的数量为 orders_count，可以在 M 上定义 OrdersCount int64 `gorm:"->;-:migration"` 字段接收。
+// criteria 用于筛选参与统计的关联记录，可以为 nil。只支持直接关联，不支持嵌套的关联。
+func (r *GormStore[M]) WithCount(relation string, criteria *Criteria) *GormStore[M] {
+	return r.withRelationAgg(relationAgg{relation: relation, fn: AggCount, criteria: criteria})
+}
+
+// WithSum 附带关联记录字段的合计，列名为 <relation>_sum_<column>，例如 orders_sum_amount，用法同 WithCount
+func (r *GormStore[M]) WithSum(relation, column string, criteria *Criteria) *GormStore[M] {
+	return r.withRelationAgg(relationAgg{relation: relation, fn: AggSum, column: column, criteria: criteria})
+}
+
+// WithAvg 附带关联记录字段的平均值，列名为 <relation>_avg_<column>，用法同 WithCount
+func (r *GormStore[M]) WithAvg(relation, column string, criteria *Criteria) *GormStore[M] {
+	return r.withRelationAgg(relationAgg{relation: relation, fn: AggAvg, column: column, criteria: criteria})
+}
+
+// WithMin 附带关联记录字段的最小值，列名为 <relation>_min_<column>，用法同 WithCount
+func (r *GormStore[M]) WithMin(relation, column string, criteria *Criteria) *GormStore[M] {
+	return r.withRelationAgg(relationAgg{relation: relation, fn: AggMin, column: column, criteria: criteria})
+}
+
+// WithMax 附带关联记录字段的最大值，列名为 <relation>_max_<column>，用法同 WithCount
+func (r *GormStore[M]) WithMax(relation, column string, criteria *Criteria) *GormStore[M] {
+	return r.withRelationAgg(relationAgg{relation: relation, fn: AggMax, column: column, criteria: criteria})
+}
+
+func (r *GormStore[M]) withRelationAgg(agg relationAgg) *GormStore[M] {
+	nr := r.clone()
+	// 复制 criteria，nil 时为空的 Criteria
+	agg.criteria = agg.criteria.Clone()
+	nr.relationAggs = append(nr.relationAggs, agg)
+	return nr
+}
+
+// presentFind 构建读取模型的查询，有关联聚合时在查询的列中添加聚合子查询
+func (r *GormStore[M]) presentFind(ctx context.Context, criteria *Criteria) *gorm.DB {
+	db := r.presentRead(ctx, criteria)
+	if len(r.relationAggs) == 0 {
+		return db
+	}
+	s, err := r.parseSchema()
+	if err != nil {
+		_ = db.AddError(err)
+		return db
+	}
+	aliases := make([]string, 0, len(r.relationAggs))
+	for _, agg := range r.relationAggs {
+		aliases = append(aliases, agg.alias(db.NamingStrategy))
+	}
+
+	var selects []string
+	var vars []any
+	switch {
+	case len(r.columns) > 0:
+		for _, column := range r.columns {
+			if slices.Contains(aliases, column) {
+				continue
+			}
+			selects = append(selects, "?")
+			vars = append(vars, clause.Column{Name: column})
+		}
+	case len(r.hidden) > 0:
+		for _, field := range s.Fields {
+			if field.DBName == "" || !field.Readable || isVirtualField(field) || slices.Contains(aliases, field.DBName) ||
+				slices.Contains(r.hidden, field.Name) || slices.Contains(r.hidden, field.DBName) {
+				continue
+			}
+			selects = append(selects, "?")
+			vars = append(vars, clause.Column{Table: clause.CurrentTable, Name: field.DBName})
+		}
+	default:
+		selects = append(selects, "?.*")
+		vars = append(vars, clause.Table{Name: clause.CurrentTable})
+	}
+	for i, agg := range r.relationAggs {
+		sub, err := agg.subquery(db, s)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		selects = append(selects, "(?) AS ?")
+		vars = append(vars, sub, clause.Column{Name: aliases[i]})
+	}
+	return db.Select(strings.Join(selects, ", "), vars...)
+}
+
+// isVirtualField 判断字段是否是只读且不参与迁移的字段，例如接收聚合列的 OrdersCount，这些字段不是表中的列
+func isVirtualField(field *schema.Field) bool {
+	return field.IgnoreMigration && field.Readable && !field.Creatable && !field.Updatable
+}
+
+// omitColumns Hidden 时排除的列，gorm 会把只读字段当作表中的列查询，因此同时排除不是表中的列的字段
+func (r *GormStore[M]) omitColumns() []string {
+	s, err := r.parseSchema()
+	if err != nil {
+		return r.hidden
+	}
+	omit := append([]string(nil), r.hidden...)
+	for _, field := range s.Fields {
+		if field.DBName != "" && isVirtualField(field) {
+			omit = append(omit, field.DBName)
+		}
+	}
+	return omit
+}
+
+// alias 聚合列的列名，按 gorm 的命名规则与 M 上的字段对应
+func (agg relationAgg) alias(namer schema.Namer) string {
+	alias := namer.ColumnName("", agg.relation) + "_" + strings.ToLower(string(agg.fn))
+	if agg.column != "" {
+		alias += "_" + namer.ColumnName("", agg.column)
+	}
+	return alias
+}
+
+// subquery 生成关联聚合的子查询，column 必须是关联模型的字段
+func (agg relationAgg) subquery(tx *gorm.DB, s *schema.Schema) (*gorm.DB, error) {
+	if strings.Contains(agg.relation, ".") {
+		return nil, fmt.Errorf("relation aggregate does not support nested relation: %s", agg.relation)
+	}
+	rel, ok := s.Relationships.Relations[agg.relation]
+	if !ok {
+		return nil, fmt.Errorf("relation %s not found in %s", agg.relation, s.Name)
+	}
+	sub, err := relationSubquery(tx, s, s.Table, []string{agg.relation}, agg.criteria)
+	if err != nil {
+		return nil, err
+	}
+	if agg.fn == AggCount {
+		return sub.Select("COUNT(*)"), nil
+	}
+	field := rel.FieldSchema.LookUpField(agg.column)
+	if field == nil || field.DBName == "" {
+		return nil, fmt.Errorf("column %s not found in %s", agg.column, rel.FieldSchema.Name)
+	}
+	return sub.Select(string(agg.fn)+"(?)", clause.Column{Table: clause.CurrentTable, Name: field.DBName}), nil
+}
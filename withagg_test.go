@@ -0,0 +1,86 @@
+package storeit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormStore_WithCount(t *testing.T) {
+	db := seedRelationModels(t)
+	base := New[RelUser](db)
+	ctx := context.Background()
+
+	// 不使用 WithCount 时聚合列为零值
+	users, err := base.Find(ctx, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Len(t, users, 3)
+	assert.Zero(t, users[0].OrdersCount)
+
+	store := base.
+		WithCount("Orders", nil).
+		WithSum("Orders", "amount", nil).
+		WithMax("Orders", "Amount", nil).
+		WithCount("Roles", nil)
+	users, err = store.Find(ctx, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Len(t, users, 3)
+	// 已删除的订单不计入
+	assert.Equal(t, []int64{2, 1, 0}, []int64{users[0].OrdersCount, users[1].OrdersCount, users[2].OrdersCount})
+	assert.Equal(t, int64(200), users[0].OrdersSumAmount)
+	assert.Equal(t, int64(150), users[0].OrdersMaxAmount)
+	assert.Equal(t, int64(80), users[1].OrdersMaxAmount)
+	assert.Zero(t, users[2].OrdersMaxAmount)
+	assert.Equal(t, []int64{2, 1, 0}, []int64{users[0].RolesCount, users[1].RolesCount, users[2].RolesCount})
+	assert.Equal(t, "Alice", users[0].Name)
+
+	// 筛选参与统计的关联记录，按聚合列排序
+	user, err := base.WithCount("Orders", NewCriteria().WhereGt("amount", 60)).First(ctx, NewCriteria().OrderDesc("orders_count").OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", user.Name)
+	assert.Equal(t, int64(1), user.OrdersCount)
+
+	found, err := store.FindByID(ctx, users[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), found.OrdersCount)
+
+	// Count 和 Pluck 不受影响
+	count, err := store.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	var names []string
+	assert.NoError(t, store.Pluck(ctx, "name", &names, NewCriteria().OrderAsc("id")))
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, names)
+
+	// 指定列和隐藏列
+	users, err = store.Columns([]string{"id"}).Find(ctx, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Empty(t, users[0].Name)
+	assert.Equal(t, int64(2), users[0].OrdersCount)
+	users, err = store.Hidden([]string{"name"}).Find(ctx, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Empty(t, users[0].Name)
+	assert.NotZero(t, users[0].ID)
+	assert.Equal(t, int64(2), users[0].OrdersCount)
+
+	// 隐藏列时没有查询的聚合字段不作为表中的列查询
+	users, err = base.Hidden([]string{"name"}).WithCount("Orders", nil).Find(ctx, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Empty(t, users[0].Name)
+	assert.Equal(t, int64(2), users[0].OrdersCount)
+	assert.Zero(t, users[0].OrdersSumAmount)
+	users, err = base.Hidden([]string{"name"}).Find(ctx, NewCriteria().OrderAsc("id"))
+	assert.NoError(t, err)
+	assert.Empty(t, users[0].Name)
+	assert.NotZero(t, users[0].ID)
+	assert.Zero(t, users[0].OrdersCount)
+
+	// 未知的关联和列
+	_, err = base.WithCount("Unknown", nil).Find(ctx, nil)
+	assert.ErrorContains(t, err, "Unknown")
+	_, err = base.WithSum("Orders", "unknown", nil).Find(ctx, nil)
+	assert.ErrorContains(t, err, "unknown")
+	_, err = base.WithCount("Manager.Orders", nil).Find(ctx, nil)
+	assert.Error(t, err)
+}